@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// collectionHelper describes the item helpers of a slice or map field
+type collectionHelper struct {
+	field FieldInfo
+	item  string // Name of a single item, e.g., "tag" for the field tags
+	elem  string // Element type of a slice, value type of a map
+	key   string // Key type of a map, empty for slices
+}
+
+// collectionHelpers returns the helpers of the slice and map fields among
+// fields with -collectionHelpers, or nil otherwise. Only fields declared with
+// a slice or map type literal whose item name is given by an item=... tag or
+// derived from a plural name, e.g., "tags", get helpers.
+func (g *Generator) collectionHelpers(fields []FieldInfo) ([]collectionHelper, error) {
+	if !g.config.CollectionHelpers {
+		return nil, nil
+	}
+
+	helpers := []collectionHelper{}
+	for _, field := range fields {
+		helper := collectionHelper{field: field}
+		isCollection := false
+		if expr, err := parser.ParseExpr(field.Type); err == nil {
+			switch t := expr.(type) {
+			case *ast.ArrayType:
+				isCollection = t.Len == nil
+				helper.elem = exprToString(t.Elt)
+			case *ast.MapType:
+				isCollection = true
+				helper.key = exprToString(t.Key)
+				helper.elem = exprToString(t.Value)
+			}
+		}
+
+		if !isCollection {
+			if field.ItemName != "" {
+				return nil, fmt.Errorf("%s: field %s has an item name but is not of a slice or map type", field.Pos, field.Name)
+			}
+			continue
+		}
+
+		helper.item = field.ItemName
+		if helper.item == "" {
+			helper.item = singularName(field.APIName())
+		}
+		if helper.item == "" {
+			continue
+		}
+		for _, other := range g.info.Fields {
+			if !other.Skip && toUpperCamelCase(other.APIName()) == toUpperCamelCase(helper.item) {
+				return nil, fmt.Errorf("%s: item %s of field %s clashes with field %s", field.Pos, helper.item, field.Name, other.Name)
+			}
+		}
+		helpers = append(helpers, helper)
+	}
+	return helpers, nil
+}
+
+// singularName returns the singular of a plural name, e.g., "tag" for "tags"
+// or "entry" for "entries", or an empty string if name is not plural
+func singularName(name string) string {
+	switch {
+	case len(name) > 3 && strings.HasSuffix(name, "ies"):
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case len(name) > 1 && strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return name[:len(name)-1]
+	}
+	return ""
+}
+
+// itemField returns the field renamed to its item, so that the option naming
+// helpers derive the names of the item options, e.g., "WithTag"
+func (h collectionHelper) itemField() FieldInfo {
+	field := h.field
+	field.NameOverride = h.item
+	return field
+}
+
+// itemParam returns the parameter name of a single item, avoiding keywords
+func (h collectionHelper) itemParam() string {
+	name := toLowerCamelCase(h.item)
+	if token.IsKeyword(name) {
+		return "item"
+	}
+	return name
+}
+
+// addStatements returns the statements adding the item parameters to the
+// collection target, e.g., "b.tags", at the given indentation
+func (h collectionHelper) addStatements(indent, target string) string {
+	if h.key != "" {
+		return fmt.Sprintf("%sif %s == nil {\n%s\t%s = make(%s)\n%s}\n%s%s[key] = value\n",
+			indent, target, indent, target, h.field.Type, indent, indent, target)
+	}
+	return fmt.Sprintf("%s%s = append(%s, %s)\n", indent, target, target, h.itemParam())
+}
+
+// itemParams returns the parameters of a single item, e.g., "tag string" or
+// "key string, value string"
+func (h collectionHelper) itemParams() string {
+	if h.key != "" {
+		return fmt.Sprintf("key %s, value %s", h.key, h.elem)
+	}
+	return fmt.Sprintf("%s %s", h.itemParam(), h.elem)
+}
+
+// generateBuilderCollectionHelpers generates the builder methods adding items
+// to slice and map fields: Add<Item> and Add<Items> for slices, Put<Item> for maps
+func (g *Generator) generateBuilderCollectionHelpers(helpers []collectionHelper, builderType string) string {
+	var buf bytes.Buffer
+
+	for _, h := range helpers {
+		fieldName := toLowerCamelCase(h.field.Name)
+		target := "b." + fieldName
+		// Adding items sets the field, like its setter
+		mark := g.presenceMark("\t", "b", h.field)
+		if h.field.Required {
+			mark = fmt.Sprintf("\tb.%sSet = true\n", fieldName) + mark
+		}
+
+		if h.key != "" {
+			methodName := "Put" + toUpperCamelCase(h.item)
+			buf.WriteString(fmt.Sprintf("// %s sets the entry of key in the %s field\n", methodName, h.field.Name))
+			buf.WriteString(deprecatedComment(h.field, ""))
+			buf.WriteString(fmt.Sprintf("func (b *%s) %s(%s) *%s {\n", builderType, methodName, h.itemParams(), builderType))
+			buf.WriteString(h.addStatements("\t", target))
+			buf.WriteString(mark)
+			buf.WriteString("\treturn b\n")
+			buf.WriteString("}\n\n")
+			continue
+		}
+
+		methodName := "Add" + toUpperCamelCase(h.item)
+		buf.WriteString(fmt.Sprintf("// %s appends %s to the %s field\n", methodName, h.itemParam(), h.field.Name))
+		buf.WriteString(deprecatedComment(h.field, ""))
+		buf.WriteString(fmt.Sprintf("func (b *%s) %s(%s) *%s {\n", builderType, methodName, h.itemParams(), builderType))
+		buf.WriteString(h.addStatements("\t", target))
+		buf.WriteString(mark)
+		buf.WriteString("\treturn b\n")
+		buf.WriteString("}\n\n")
+
+		methodName = "Add" + toUpperCamelCase(h.field.APIName())
+		paramName := toLowerCamelCase(h.field.APIName())
+		buf.WriteString(fmt.Sprintf("// %s appends %s to the %s field\n", methodName, paramName, h.field.Name))
+		buf.WriteString(deprecatedComment(h.field, ""))
+		buf.WriteString(fmt.Sprintf("func (b *%s) %s(%s ...%s) *%s {\n", builderType, methodName, paramName, h.elem, builderType))
+		buf.WriteString(fmt.Sprintf("\t%s = append(%s, %s...)\n", target, target, paramName))
+		buf.WriteString(mark)
+		buf.WriteString("\treturn b\n")
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.String()
+}
+
+// generateOptionCollectionHelpers generates the options adding an item to
+// slice and map fields, e.g., WithTag appending to tags, in the option style
+// of the options constructor. Interface options use the OptionFunc adapter.
+func (g *Generator) generateOptionCollectionHelpers(helpers []collectionHelper) string {
+	var buf bytes.Buffer
+
+	optionType := g.info.Name + "Option" + g.info.TypeArgs()
+	for _, h := range helpers {
+		item := h.itemField()
+		if h.key != "" {
+			buf.WriteString(fmt.Sprintf("// %s sets the entry of key in the %s field\n", g.optionFuncName(item), h.field.Name))
+		} else {
+			buf.WriteString(fmt.Sprintf("// %s appends %s to the %s field\n", g.optionFuncName(item), h.itemParam(), h.field.Name))
+		}
+		buf.WriteString(deprecatedComment(h.field, ""))
+		buf.WriteString(fmt.Sprintf("%s(%s) %s {\n", g.optionFuncDecl(item), h.itemParams(), optionType))
+
+		switch g.config.OptionStyle {
+		case optionStyleInterface:
+			buf.WriteString(fmt.Sprintf("\treturn %sOptionFunc%s(func(s *%s) {\n", g.info.Name, g.info.TypeArgs(), g.typeName()))
+		case optionStyleError:
+			buf.WriteString(fmt.Sprintf("\treturn func(s *%s) error {\n", g.typeName()))
+		default:
+			buf.WriteString(fmt.Sprintf("\treturn func(s *%s) {\n", g.typeName()))
+		}
+		buf.WriteString(h.addStatements("\t\t", "s."+h.field.Path()))
+		buf.WriteString(g.presenceMark("\t\t", "s", h.field))
+		switch g.config.OptionStyle {
+		case optionStyleInterface:
+			buf.WriteString("\t})\n")
+		case optionStyleError:
+			buf.WriteString("\t\treturn nil\n")
+			buf.WriteString("\t}\n")
+		default:
+			buf.WriteString("\t}\n")
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.String()
+}
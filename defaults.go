@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultExprPrefix marks a default value given as a Go expression, e.g.,
+// `constructor:"default=expr:runtime.NumCPU()"`
+const defaultExprPrefix = "expr:"
+
+// valueKind classifies field types by how default values and zero checks are written
+type valueKind int
+
+const (
+	kindOther      valueKind = iota // Anything else, e.g., structs, arrays and type parameters
+	kindString                      // string and types based on it
+	kindBool                        // bool and types based on it
+	kindInt                         // Signed integers and types based on them
+	kindUint                        // Unsigned integers and types based on them
+	kindFloat                       // Floating-point numbers and types based on them
+	kindDuration                    // time.Duration
+	kindNilable                     // Pointers, functions, channels and interfaces
+	kindCollection                  // Slices and maps
+)
+
+// validateDefault checks the syntax of a default=... tag value. Whether the
+// value fits the field type is checked when generating code.
+func validateDefault(value string) error {
+	if value == "" {
+		return fmt.Errorf("default value must not be empty")
+	}
+	if expr, ok := strings.CutPrefix(value, defaultExprPrefix); ok {
+		if _, err := parser.ParseExpr(expr); err != nil {
+			return fmt.Errorf("invalid expression %q: %w", expr, err)
+		}
+	}
+	return nil
+}
+
+// defaultExpr returns the Go expression of the default value of field, and
+// whether the field has one. Literals are checked against the field type;
+// expr:... values are used as written.
+func defaultExpr(field FieldInfo) (string, bool, error) {
+	if field.Default == "" {
+		return "", false, nil
+	}
+	if expr, ok := strings.CutPrefix(field.Default, defaultExprPrefix); ok {
+		return expr, true, nil
+	}
+
+	value := field.Default
+	invalid := func(err error) (string, bool, error) {
+		return "", false, fmt.Errorf("%s: field %s: invalid default %q for type %s: %w", field.Pos, field.Name, value, field.Type, err)
+	}
+
+	switch kind, qualifier := classifyType(field); kind {
+	case kindString:
+		return strconv.Quote(value), true, nil
+	case kindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return invalid(err)
+		}
+		return strconv.FormatBool(b), true, nil
+	case kindInt:
+		if _, err := strconv.ParseInt(value, 0, 64); err != nil {
+			return invalid(err)
+		}
+		if err := checkNumberLiteral(value); err != nil {
+			return invalid(err)
+		}
+		return value, true, nil
+	case kindUint:
+		if _, err := strconv.ParseUint(value, 0, 64); err != nil {
+			return invalid(err)
+		}
+		if err := checkNumberLiteral(value); err != nil {
+			return invalid(err)
+		}
+		return value, true, nil
+	case kindFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return invalid(err)
+		}
+		if err := checkNumberLiteral(value); err != nil {
+			return invalid(err)
+		}
+		return value, true, nil
+	case kindDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return invalid(err)
+		}
+		return durationExpr(d, qualifier), true, nil
+	default:
+		return invalid(fmt.Errorf("use %s<Go expression> for this type", defaultExprPrefix))
+	}
+}
+
+// checkNumberLiteral checks that value is a Go number literal, optionally
+// signed, so that it can be used as written. strconv also accepts values such
+// as "Inf" or "NaN", which are not.
+func checkNumberLiteral(value string) error {
+	expr, err := parser.ParseExpr(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a Go number literal", value)
+	}
+	if unary, ok := expr.(*ast.UnaryExpr); ok && (unary.Op == token.ADD || unary.Op == token.SUB) {
+		expr = unary.X
+	}
+	if lit, ok := expr.(*ast.BasicLit); !ok || (lit.Kind != token.INT && lit.Kind != token.FLOAT) {
+		return fmt.Errorf("%q is not a Go number literal", value)
+	}
+	return nil
+}
+
+// durationExpr renders d as a constant expression using the largest unit of the
+// time package that divides it, e.g., "30 * time.Second".
+// qualifier is the name the time package is imported as.
+func durationExpr(d time.Duration, qualifier string) string {
+	units := []struct {
+		name string
+		unit time.Duration
+	}{
+		{"Hour", time.Hour},
+		{"Minute", time.Minute},
+		{"Second", time.Second},
+		{"Millisecond", time.Millisecond},
+		{"Microsecond", time.Microsecond},
+		{"Nanosecond", time.Nanosecond},
+	}
+	if d == 0 {
+		return "0"
+	}
+	for _, u := range units {
+		if d%u.unit != 0 {
+			continue
+		}
+		if n := d / u.unit; n != 1 {
+			return fmt.Sprintf("%d * %s.%s", n, qualifier, u.name)
+		}
+		return fmt.Sprintf("%s.%s", qualifier, u.name)
+	}
+	return strconv.FormatInt(int64(d), 10)
+}
+
+// zeroCheck returns a condition reporting whether the variable name of the
+// type of field holds the zero value, e.g., `port == 0` or `handler == nil`
+func zeroCheck(field FieldInfo, name string) string {
+	switch kind, _ := classifyType(field); kind {
+	case kindString:
+		return name + ` == ""`
+	case kindBool:
+		return "!" + name
+	case kindInt, kindUint, kindFloat, kindDuration:
+		return name + " == 0"
+	case kindNilable, kindCollection:
+		return name + " == nil"
+	default:
+		return fmt.Sprintf("reflect.ValueOf(&%s).Elem().IsZero()", name)
+	}
+}
+
+// classifyType returns the kind of the type of field and, for time.Duration,
+// the name the time package is imported as. The resolved type is used when
+// available (type-checked loading); otherwise only predeclared types,
+// time.Duration and type literals are recognized.
+func classifyType(field FieldInfo) (valueKind, string) {
+	qualifier := "time"
+	expr, err := parser.ParseExpr(field.Type)
+	if err != nil {
+		return kindOther, qualifier
+	}
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			qualifier = ident.Name
+		}
+	}
+
+	if field.TypeInfo != nil {
+		return classifyResolvedType(field.TypeInfo), qualifier
+	}
+
+	switch t := expr.(type) {
+	case *ast.StarExpr, *ast.FuncType, *ast.ChanType, *ast.InterfaceType:
+		return kindNilable, qualifier
+	case *ast.MapType:
+		return kindCollection, qualifier
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return kindCollection, qualifier
+		}
+	case *ast.SelectorExpr:
+		if qualifier == "time" && t.Sel.Name == "Duration" {
+			return kindDuration, qualifier
+		}
+	case *ast.Ident:
+		if obj := types.Universe.Lookup(t.Name); obj != nil {
+			if _, ok := obj.(*types.TypeName); ok {
+				return classifyResolvedType(obj.Type()), qualifier
+			}
+		}
+	}
+	return kindOther, qualifier
+}
+
+// classifyResolvedType returns the kind of a type resolved by go/types
+func classifyResolvedType(t types.Type) valueKind {
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration" {
+			return kindDuration
+		}
+	}
+	if _, ok := t.(*types.TypeParam); ok {
+		return kindOther
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case info&types.IsString != 0:
+			return kindString
+		case info&types.IsBoolean != 0:
+			return kindBool
+		case info&types.IsUnsigned != 0:
+			return kindUint
+		case info&types.IsInteger != 0:
+			return kindInt
+		case info&types.IsFloat != 0:
+			return kindFloat
+		}
+	case *types.Pointer, *types.Signature, *types.Chan, *types.Interface:
+		return kindNilable
+	case *types.Slice, *types.Map:
+		return kindCollection
+	}
+	return kindOther
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultExpr(t *testing.T) {
+	durationType := types.NewNamed(types.NewTypeName(0, types.NewPackage("time", "time"), "Duration", nil), types.Typ[types.Int64], nil)
+	levelType := types.NewNamed(types.NewTypeName(0, types.NewPackage("example.com/log", "log"), "Level", nil), types.Typ[types.Uint8], nil)
+
+	tests := []struct {
+		name   string
+		field  FieldInfo
+		expect string
+	}{
+		{"string", FieldInfo{Type: "string", Default: "localhost"}, `"localhost"`},
+		{"string with quotes", FieldInfo{Type: "string", Default: `say "hi"`}, `"say \"hi\""`},
+		{"int", FieldInfo{Type: "int", Default: "8080"}, "8080"},
+		{"hex uint", FieldInfo{Type: "uint32", Default: "0xff"}, "0xff"},
+		{"float", FieldInfo{Type: "float64", Default: "0.5"}, "0.5"},
+		{"negative float", FieldInfo{Type: "float64", Default: "-1e3"}, "-1e3"},
+		{"bool", FieldInfo{Type: "bool", Default: "1"}, "true"},
+		{"duration", FieldInfo{Type: "time.Duration", Default: "30s"}, "30 * time.Second"},
+		{"duration single unit", FieldInfo{Type: "time.Duration", Default: "1h"}, "time.Hour"},
+		{"duration mixed units", FieldInfo{Type: "time.Duration", Default: "1m30s"}, "90 * time.Second"},
+		{"aliased duration", FieldInfo{Type: "tm.Duration", Default: "2m", TypeInfo: durationType}, "2 * tm.Minute"},
+		{"duration alias", FieldInfo{Type: "Timeout", Default: "5s", TypeInfo: types.NewAlias(types.NewTypeName(0, nil, "Timeout", nil), durationType)}, "5 * time.Second"},
+		{"named type", FieldInfo{Type: "log.Level", Default: "2", TypeInfo: levelType}, "2"},
+		{"expression", FieldInfo{Type: "int", Default: "expr:runtime.NumCPU()"}, "runtime.NumCPU()"},
+		{"expression for any type", FieldInfo{Type: "*http.Client", Default: "expr:http.DefaultClient"}, "http.DefaultClient"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, ok, err := defaultExpr(tt.field)
+			if err != nil {
+				t.Fatalf("defaultExpr failed: %v", err)
+			}
+			if !ok || expr != tt.expect {
+				t.Errorf("defaultExpr() = (%q, %v), want (%q, true)", expr, ok, tt.expect)
+			}
+		})
+	}
+
+	if _, ok, err := defaultExpr(FieldInfo{Type: "int"}); ok || err != nil {
+		t.Errorf("field without default: got (%v, %v), want (false, nil)", ok, err)
+	}
+}
+
+func TestDefaultExprErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		field FieldInfo
+	}{
+		{"invalid int", FieldInfo{Type: "int", Default: "many"}},
+		{"negative uint", FieldInfo{Type: "uint", Default: "-1"}},
+		{"invalid bool", FieldInfo{Type: "bool", Default: "yes"}},
+		{"infinite float", FieldInfo{Type: "float64", Default: "inf"}},
+		{"signed infinite float", FieldInfo{Type: "float32", Default: "-Infinity"}},
+		{"NaN", FieldInfo{Type: "float64", Default: "NaN"}},
+		{"invalid duration", FieldInfo{Type: "time.Duration", Default: "30"}},
+		{"unknown type", FieldInfo{Type: "Level", Default: "2"}},
+		{"pointer", FieldInfo{Type: "*string", Default: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.field.Name = "field"
+			tt.field.Pos = "test.go:3"
+			_, _, err := defaultExpr(tt.field)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.HasPrefix(err.Error(), "test.go:3: field field: invalid default") {
+				t.Errorf("error should point at the field, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestDurationExpr(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		expect string
+	}{
+		{0, "0"},
+		{time.Nanosecond, "time.Nanosecond"},
+		{1500 * time.Millisecond, "1500 * time.Millisecond"},
+		{2 * time.Hour, "2 * time.Hour"},
+	}
+
+	for _, tt := range tests {
+		if got := durationExpr(tt.d, "time"); got != tt.expect {
+			t.Errorf("durationExpr(%v) = %q, want %q", tt.d, got, tt.expect)
+		}
+	}
+}
+
+func TestZeroCheck(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		expect    string
+	}{
+		{"string", `v == ""`},
+		{"bool", "!v"},
+		{"int64", "v == 0"},
+		{"float32", "v == 0"},
+		{"time.Duration", "v == 0"},
+		{"*User", "v == nil"},
+		{"[]string", "v == nil"},
+		{"map[string]int", "v == nil"},
+		{"func()", "v == nil"},
+		{"error", "v == nil"},
+		{"[4]byte", "reflect.ValueOf(&v).Elem().IsZero()"},
+		{"Address", "reflect.ValueOf(&v).Elem().IsZero()"},
+	}
+
+	for _, tt := range tests {
+		if got := zeroCheck(FieldInfo{Type: tt.fieldType}, "v"); got != tt.expect {
+			t.Errorf("zeroCheck(%s) = %q, want %q", tt.fieldType, got, tt.expect)
+		}
+	}
+}
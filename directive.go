@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"io"
+	"slices"
+	"strings"
+)
+
+// directivePrefix marks the doc comment line that configures generation for a struct, e.g.:
+//
+//	//constructor:gen builder,options init=setup returnValue
+const directivePrefix = "//constructor:gen"
+
+// findDirective returns the arguments of the //constructor:gen line in doc,
+// and whether such a line was found
+func findDirective(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+
+	for _, comment := range doc.List {
+		args, ok := strings.CutPrefix(comment.Text, directivePrefix)
+		if !ok {
+			continue
+		}
+		// Require a separator so that e.g. //constructor:generate does not match
+		if args != "" && args[0] != ' ' && args[0] != '\t' {
+			continue
+		}
+		return strings.TrimSpace(args), true
+	}
+
+	return "", false
+}
+
+// applyDirective returns a copy of base with the settings of a //constructor:gen
+// directive applied. Directive arguments use the command line flag names without
+// the leading dash: "key=value" for valued flags, "key" for boolean flags, and a
+// bare comma-separated list for the constructor types.
+func applyDirective(base *GeneratorConfig, directive string) (*GeneratorConfig, error) {
+	config := *base
+	config.ConstructorTypes = slices.Clone(base.ConstructorTypes)
+
+	fs := flag.NewFlagSet(directivePrefix, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	registerConfigFlags(fs, &config)
+
+	args := []string{}
+	for _, arg := range strings.Fields(directive) {
+		name, _, _ := strings.Cut(arg, "=")
+		if fs.Lookup(name) == nil && !strings.Contains(arg, "=") {
+			// A bare word that is not a flag lists the constructor types
+			arg = "constructorTypes=" + arg
+		}
+		args = append(args, "-"+arg)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("invalid %s directive: %w", directivePrefix, err)
+	}
+
+	return &config, nil
+}
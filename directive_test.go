@@ -0,0 +1,82 @@
+package main
+
+import (
+	"go/ast"
+	"reflect"
+	"testing"
+)
+
+func TestFindDirective(t *testing.T) {
+	tests := []struct {
+		name          string
+		comments      []string
+		expectArgs    string
+		expectPresent bool
+	}{
+		{"no comment", nil, "", false},
+		{"plain doc", []string{"// User is a user"}, "", false},
+		{"bare directive", []string{"// User is a user", "//constructor:gen"}, "", true},
+		{"with args", []string{"//constructor:gen builder,options init=setup returnValue"}, "builder,options init=setup returnValue", true},
+		{"spaced comment", []string{"// constructor:gen builder"}, "", false},
+		{"other directive", []string{"//constructor:generate builder"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc *ast.CommentGroup
+			if tt.comments != nil {
+				doc = &ast.CommentGroup{}
+				for _, text := range tt.comments {
+					doc.List = append(doc.List, &ast.Comment{Text: text})
+				}
+			}
+
+			args, present := findDirective(doc)
+			if args != tt.expectArgs || present != tt.expectPresent {
+				t.Errorf("findDirective() = (%q, %v), want (%q, %v)", args, present, tt.expectArgs, tt.expectPresent)
+			}
+		})
+	}
+}
+
+func TestApplyDirective(t *testing.T) {
+	base := &GeneratorConfig{
+		ConstructorTypes: []string{"allArgs"},
+		SetterPrefix:     "With",
+		WithGetter:       true,
+	}
+
+	config, err := applyDirective(base, "builder,options init=setup returnValue withGetter=false")
+	if err != nil {
+		t.Fatalf("applyDirective failed: %v", err)
+	}
+
+	expected := &GeneratorConfig{
+		ConstructorTypes: []string{"builder", "options"},
+		InitFunc:         "setup",
+		ReturnValue:      true,
+		SetterPrefix:     "With",
+		WithGetter:       false,
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("applyDirective() = %+v, want %+v", config, expected)
+	}
+
+	// The base configuration must not be modified
+	if len(base.ConstructorTypes) != 1 || base.ConstructorTypes[0] != "allArgs" || base.ReturnValue {
+		t.Errorf("applyDirective modified the base config: %+v", base)
+	}
+
+	// An empty directive keeps the base configuration
+	config, err = applyDirective(base, "")
+	if err != nil {
+		t.Fatalf("applyDirective failed: %v", err)
+	}
+	if !reflect.DeepEqual(config, base) {
+		t.Errorf("applyDirective() = %+v, want %+v", config, base)
+	}
+
+	if _, err := applyDirective(base, "unknown=1"); err == nil {
+		t.Error("Expected error for unknown directive option")
+	}
+}
@@ -0,0 +1,14 @@
+package allargs
+
+//go:generate go run ../../. -type=Point -constructorTypes=allArgs -withGetter -getterStyle=plain -receiver=value
+
+// Point represents an immutable 2D point
+// This example demonstrates:
+// 1. Idiomatic getters named after the field, e.g., X() instead of GetX()
+// 2. Value receivers for small value types with -receiver=value
+// 3. Overriding a getter name with constructor:"getter=<Name>"
+type Point struct {
+	x      float64
+	y      float64
+	origin bool `constructor:"getter=IsOrigin"`
+}
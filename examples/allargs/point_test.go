@@ -0,0 +1,21 @@
+package allargs
+
+import "testing"
+
+func TestPointGetters(t *testing.T) {
+	point := NewPoint(3, 4, false)
+
+	// Value receivers work on both values and pointers
+	value := *point
+	if value.X() != 3 {
+		t.Errorf("X() = %f, want 3", value.X())
+	}
+
+	if point.Y() != 4 {
+		t.Errorf("Y() = %f, want 4", point.Y())
+	}
+
+	if point.IsOrigin() {
+		t.Error("IsOrigin() = true, want false")
+	}
+}
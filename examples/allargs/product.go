@@ -1,14 +1,20 @@
 package allargs
 
-//go:generate go run ../../. -type=Product -constructorTypes=allArgs -withGetter
+//go:generate go run ../../. -type=Product -constructorTypes=allArgs -withGetter -omitDeprecated
 
 // Product represents a product with getters
 // This example demonstrates:
 // 1. All-args constructor with automatic getter generation
 // 2. Skip getter for specific fields with constructor:"getter:false"
+// 3. Deprecated fields left out of the constructor with -omitDeprecated
 type Product struct {
-	id          int     // Will have constructor parameter and getter
-	name        string  // Will have constructor parameter and getter
-	price       float64 // Will have constructor parameter and getter
-	description string  `constructor:"getter:false"` // Will have constructor parameter but NO getter
+	id          int     // Catalog identifier
+	name        string  // Display name
+	price       float64 // Unit price in euros
+	description string  `constructor:"getter:false"` // Marketing text, with no getter
+
+	// Stock keeping unit of the legacy catalog.
+	//
+	// Deprecated: products are identified by id.
+	sku string
 }
@@ -3,6 +3,18 @@ package allargs
 // Code generated by constructor. DO NOT EDIT.
 
 // NewProduct creates a new Product
+//
+// Product represents a product with getters
+// This example demonstrates:
+// 1. All-args constructor with automatic getter generation
+// 2. Skip getter for specific fields with constructor:"getter:false"
+// 3. Deprecated fields left out of the constructor with -omitDeprecated
+//
+// Parameters:
+//   - id: Catalog identifier
+//   - name: Display name
+//   - price: Unit price in euros
+//   - description: Marketing text, with no getter
 func NewProduct(id int, name string, price float64, description string) *Product {
 	return &Product{
 		id:          id,
@@ -13,16 +25,31 @@ func NewProduct(id int, name string, price float64, description string) *Product
 }
 
 // GetId returns the id field
+//
+// Catalog identifier
 func (p *Product) GetId() int {
 	return p.id
 }
 
 // GetName returns the name field
+//
+// Display name
 func (p *Product) GetName() string {
 	return p.name
 }
 
 // GetPrice returns the price field
+//
+// Unit price in euros
 func (p *Product) GetPrice() float64 {
 	return p.price
 }
+
+// GetSku returns the sku field
+//
+// Stock keeping unit of the legacy catalog.
+//
+// Deprecated: products are identified by id.
+func (p *Product) GetSku() string {
+	return p.sku
+}
@@ -52,3 +52,15 @@ func TestProductGetters(t *testing.T) {
 		t.Error("GetDescription() should not exist due to constructor:\"getter:false\" tag")
 	}
 }
+
+func TestProductDeprecatedField(t *testing.T) {
+	// sku is deprecated, so -omitDeprecated leaves it out of NewProduct
+	if n := reflect.TypeOf(NewProduct).NumIn(); n != 4 {
+		t.Errorf("Expected NewProduct to take 4 parameters, got %d", n)
+	}
+
+	product := NewProduct(3, "Keyboard", 49.99, "Mechanical keyboard")
+	if product.GetSku() != "" {
+		t.Errorf("Expected empty sku, got %q", product.GetSku())
+	}
+}
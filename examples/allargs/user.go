@@ -10,10 +10,10 @@ import "time"
 // 2. Field skipping with constructor:"-"
 // 3. Getter-only fields with constructor:"setter:false"
 type User struct {
-	id        int               // Will be included in constructor
-	name      string            // Will be included in constructor
-	email     string            // Will be included in constructor
-	createdAt time.Time         // Will be included in constructor
+	id        int               // Unique user identifier
+	name      string            // Full name
+	email     string            // Contact address
+	createdAt time.Time         // Time the account was created
 	internal  string            `constructor:"-"`            // Completely skipped
 	metadata  map[string]string `constructor:"setter:false"` // Only getter, not in constructor
 }
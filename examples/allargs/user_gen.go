@@ -5,6 +5,18 @@ import "time"
 // Code generated by constructor. DO NOT EDIT.
 
 // NewUser creates a new User
+//
+// User represents a user in the system
+// This example demonstrates:
+// 1. Basic all-args constructor
+// 2. Field skipping with constructor:"-"
+// 3. Getter-only fields with constructor:"setter:false"
+//
+// Parameters:
+//   - id: Unique user identifier
+//   - name: Full name
+//   - email: Contact address
+//   - createdAt: Time the account was created
 func NewUser(id int, name string, email string, createdAt time.Time) *User {
 	return &User{
 		id:        id,
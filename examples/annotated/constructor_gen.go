@@ -0,0 +1,132 @@
+package annotated
+
+import "time"
+
+// Code generated by constructor. DO NOT EDIT.
+
+// NewAccount creates a new Account
+//
+// Account represents a user account
+// This example demonstrates:
+// 1. Annotation-driven discovery with a package-level go:generate line
+// 2. Per-struct options in //constructor:gen directives
+// 3. A single consolidated constructor_gen.go for the package
+func NewAccount(id int64, email string) *Account {
+	return &Account{
+		id:    id,
+		email: email,
+	}
+}
+
+// AccountBuilder is a builder for Account
+//
+// Account represents a user account
+// This example demonstrates:
+// 1. Annotation-driven discovery with a package-level go:generate line
+// 2. Per-struct options in //constructor:gen directives
+// 3. A single consolidated constructor_gen.go for the package
+type AccountBuilder struct {
+	id    int64
+	email string
+}
+
+// NewAccountBuilder creates a new AccountBuilder
+func NewAccountBuilder() *AccountBuilder {
+	return &AccountBuilder{}
+}
+
+// WithId sets the id field
+func (b *AccountBuilder) WithId(id int64) *AccountBuilder {
+	b.id = id
+	return b
+}
+
+// WithEmail sets the email field
+func (b *AccountBuilder) WithEmail(email string) *AccountBuilder {
+	b.email = email
+	return b
+}
+
+// Build builds the Account
+func (b *AccountBuilder) Build() *Account {
+	v := &Account{
+		id:    b.id,
+		email: b.email,
+	}
+	return v
+}
+
+// GetId returns the id field
+func (a *Account) GetId() int64 {
+	return a.id
+}
+
+// GetEmail returns the email field
+func (a *Account) GetEmail() string {
+	return a.email
+}
+
+// DeviceOption is a functional option for configuring Device
+//
+// Device represents a trusted device of a session
+// Its token option would clash with the one of Session, so auto naming
+// prefixes its option functions with the struct name
+type DeviceOption func(*Device)
+
+// WithDeviceToken sets the token field
+func WithDeviceToken(token string) DeviceOption {
+	return func(s *Device) {
+		s.token = token
+	}
+}
+
+// WithDeviceName sets the name field
+func WithDeviceName(name string) DeviceOption {
+	return func(s *Device) {
+		s.name = name
+	}
+}
+
+// NewDeviceWithOptions creates a new Device with functional options
+//
+// Device represents a trusted device of a session
+// Its token option would clash with the one of Session, so auto naming
+// prefixes its option functions with the struct name
+func NewDeviceWithOptions(opts ...DeviceOption) *Device {
+	v := &Device{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// SessionOption is a functional option for configuring Session
+//
+// Session represents a login session
+type SessionOption func(*Session)
+
+// WithToken sets the token field
+func WithToken(token string) SessionOption {
+	return func(s *Session) {
+		s.token = token
+	}
+}
+
+// WithExpires sets the expires field
+func WithExpires(expires time.Duration) SessionOption {
+	return func(s *Session) {
+		s.expires = expires
+	}
+}
+
+// NewSessionWithOptions creates a new Session with functional options
+//
+// Session represents a login session
+func NewSessionWithOptions(opts ...SessionOption) Session {
+	v := &Session{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	v.initialize()
+	return *v
+}
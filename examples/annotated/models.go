@@ -0,0 +1,47 @@
+package annotated
+
+import "time"
+
+//go:generate go run ../../. .
+
+// Account represents a user account
+// This example demonstrates:
+// 1. Annotation-driven discovery with a package-level go:generate line
+// 2. Per-struct options in //constructor:gen directives
+// 3. A single consolidated constructor_gen.go for the package
+//
+//constructor:gen allArgs,builder setterPrefix=With withGetter
+type Account struct {
+	id    int64
+	email string
+}
+
+// Session represents a login session
+//
+//constructor:gen options returnValue init=initialize
+type Session struct {
+	token   string
+	expires time.Duration
+}
+
+// initialize sets a default expiry
+func (s *Session) initialize() {
+	if s.expires == 0 {
+		s.expires = time.Hour
+	}
+}
+
+// Device represents a trusted device of a session
+// Its token option would clash with the one of Session, so auto naming
+// prefixes its option functions with the struct name
+//
+//constructor:gen options optionNaming=auto
+type Device struct {
+	token string
+	name  string
+}
+
+// AuditEntry has no directive, so no constructor is generated for it
+type AuditEntry struct {
+	action string
+}
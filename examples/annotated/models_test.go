@@ -0,0 +1,52 @@
+package annotated
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccountConstructors(t *testing.T) {
+	account := NewAccount(1, "alice@example.com")
+
+	if account.GetId() != 1 {
+		t.Errorf("Expected id 1, got %d", account.GetId())
+	}
+
+	if account.GetEmail() != "alice@example.com" {
+		t.Errorf("Expected email alice@example.com, got %s", account.GetEmail())
+	}
+
+	built := NewAccountBuilder().
+		WithId(2).
+		WithEmail("bob@example.com").
+		Build()
+
+	if built.id != 2 || built.email != "bob@example.com" {
+		t.Errorf("Unexpected account from builder: %+v", built)
+	}
+}
+
+func TestSessionWithOptions(t *testing.T) {
+	session := NewSessionWithOptions(WithToken("abc"))
+
+	if session.token != "abc" {
+		t.Errorf("Expected token abc, got %s", session.token)
+	}
+
+	// initialize() sets the default expiry
+	if session.expires != time.Hour {
+		t.Errorf("Expected default expires 1h, got %v", session.expires)
+	}
+}
+
+func TestDeviceWithPrefixedOptions(t *testing.T) {
+	device := NewDeviceWithOptions(WithDeviceToken("xyz"), WithDeviceName("laptop"))
+
+	if device.token != "xyz" {
+		t.Errorf("Expected token xyz, got %s", device.token)
+	}
+
+	if device.name != "laptop" {
+		t.Errorf("Expected name laptop, got %s", device.name)
+	}
+}
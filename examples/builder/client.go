@@ -0,0 +1,19 @@
+package builder
+
+import "time"
+
+//go:generate go run ../../. -type=Client -constructorTypes=builder,options -collectionHelpers -toBuilder -withGetter
+
+// Client represents an HTTP client configuration
+// This example demonstrates:
+// 1. AddX/AddXs builder methods appending to slice fields
+// 2. PutX builder methods and WithX options setting map entries
+// 3. Item names given with constructor:"item=<Name>" where the field name is not a simple plural
+// 4. ToBuilder copying the collections, so that adding items leaves the original unchanged
+type Client struct {
+	baseURL string
+	timeout time.Duration
+	headers map[string]string
+	plugins []string
+	aliases []string `constructor:"item=alias"`
+}
@@ -0,0 +1,231 @@
+package builder
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Code generated by constructor. DO NOT EDIT.
+
+// ClientBuilder is a builder for Client
+//
+// Client represents an HTTP client configuration
+// This example demonstrates:
+// 1. AddX/AddXs builder methods appending to slice fields
+// 2. PutX builder methods and WithX options setting map entries
+// 3. Item names given with constructor:"item=<Name>" where the field name is not a simple plural
+// 4. ToBuilder copying the collections, so that adding items leaves the original unchanged
+type ClientBuilder struct {
+	baseURL string
+	timeout time.Duration
+	headers map[string]string
+	plugins []string
+	aliases []string
+}
+
+// NewClientBuilder creates a new ClientBuilder
+func NewClientBuilder() *ClientBuilder {
+	return &ClientBuilder{}
+}
+
+// NewClientBuilderFrom creates a new ClientBuilder initialized with the fields of v
+func NewClientBuilderFrom(v *Client) *ClientBuilder {
+	return &ClientBuilder{
+		baseURL: v.baseURL,
+		timeout: v.timeout,
+		headers: maps.Clone(v.headers),
+		plugins: slices.Clone(v.plugins),
+		aliases: slices.Clone(v.aliases),
+	}
+}
+
+// ToBuilder returns a new ClientBuilder initialized with the fields of c
+func (c *Client) ToBuilder() *ClientBuilder {
+	return NewClientBuilderFrom(c)
+}
+
+// BaseURL sets the baseURL field
+func (b *ClientBuilder) BaseURL(baseURL string) *ClientBuilder {
+	b.baseURL = baseURL
+	return b
+}
+
+// Timeout sets the timeout field
+func (b *ClientBuilder) Timeout(timeout time.Duration) *ClientBuilder {
+	b.timeout = timeout
+	return b
+}
+
+// Headers sets the headers field
+func (b *ClientBuilder) Headers(headers map[string]string) *ClientBuilder {
+	b.headers = headers
+	return b
+}
+
+// Plugins sets the plugins field
+func (b *ClientBuilder) Plugins(plugins []string) *ClientBuilder {
+	b.plugins = plugins
+	return b
+}
+
+// Aliases sets the aliases field
+func (b *ClientBuilder) Aliases(aliases []string) *ClientBuilder {
+	b.aliases = aliases
+	return b
+}
+
+// PutHeader sets the entry of key in the headers field
+func (b *ClientBuilder) PutHeader(key string, value string) *ClientBuilder {
+	if b.headers == nil {
+		b.headers = make(map[string]string)
+	}
+	b.headers[key] = value
+	return b
+}
+
+// AddPlugin appends plugin to the plugins field
+func (b *ClientBuilder) AddPlugin(plugin string) *ClientBuilder {
+	b.plugins = append(b.plugins, plugin)
+	return b
+}
+
+// AddPlugins appends plugins to the plugins field
+func (b *ClientBuilder) AddPlugins(plugins ...string) *ClientBuilder {
+	b.plugins = append(b.plugins, plugins...)
+	return b
+}
+
+// AddAlias appends alias to the aliases field
+func (b *ClientBuilder) AddAlias(alias string) *ClientBuilder {
+	b.aliases = append(b.aliases, alias)
+	return b
+}
+
+// AddAliases appends aliases to the aliases field
+func (b *ClientBuilder) AddAliases(aliases ...string) *ClientBuilder {
+	b.aliases = append(b.aliases, aliases...)
+	return b
+}
+
+// Build builds the Client
+func (b *ClientBuilder) Build() *Client {
+	v := &Client{
+		baseURL: b.baseURL,
+		timeout: b.timeout,
+		headers: b.headers,
+		plugins: b.plugins,
+		aliases: b.aliases,
+	}
+	return v
+}
+
+// ClientOption is a functional option for configuring Client
+//
+// Client represents an HTTP client configuration
+// This example demonstrates:
+// 1. AddX/AddXs builder methods appending to slice fields
+// 2. PutX builder methods and WithX options setting map entries
+// 3. Item names given with constructor:"item=<Name>" where the field name is not a simple plural
+// 4. ToBuilder copying the collections, so that adding items leaves the original unchanged
+type ClientOption func(*Client)
+
+// WithBaseURL sets the baseURL field
+func WithBaseURL(baseURL string) ClientOption {
+	return func(s *Client) {
+		s.baseURL = baseURL
+	}
+}
+
+// WithTimeout sets the timeout field
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(s *Client) {
+		s.timeout = timeout
+	}
+}
+
+// WithHeaders sets the headers field
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(s *Client) {
+		s.headers = headers
+	}
+}
+
+// WithPlugins sets the plugins field
+func WithPlugins(plugins []string) ClientOption {
+	return func(s *Client) {
+		s.plugins = plugins
+	}
+}
+
+// WithAliases sets the aliases field
+func WithAliases(aliases []string) ClientOption {
+	return func(s *Client) {
+		s.aliases = aliases
+	}
+}
+
+// WithHeader sets the entry of key in the headers field
+func WithHeader(key string, value string) ClientOption {
+	return func(s *Client) {
+		if s.headers == nil {
+			s.headers = make(map[string]string)
+		}
+		s.headers[key] = value
+	}
+}
+
+// WithPlugin appends plugin to the plugins field
+func WithPlugin(plugin string) ClientOption {
+	return func(s *Client) {
+		s.plugins = append(s.plugins, plugin)
+	}
+}
+
+// WithAlias appends alias to the aliases field
+func WithAlias(alias string) ClientOption {
+	return func(s *Client) {
+		s.aliases = append(s.aliases, alias)
+	}
+}
+
+// NewClientWithOptions creates a new Client with functional options
+//
+// Client represents an HTTP client configuration
+// This example demonstrates:
+// 1. AddX/AddXs builder methods appending to slice fields
+// 2. PutX builder methods and WithX options setting map entries
+// 3. Item names given with constructor:"item=<Name>" where the field name is not a simple plural
+// 4. ToBuilder copying the collections, so that adding items leaves the original unchanged
+func NewClientWithOptions(opts ...ClientOption) *Client {
+	v := &Client{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// GetBaseURL returns the baseURL field
+func (c *Client) GetBaseURL() string {
+	return c.baseURL
+}
+
+// GetTimeout returns the timeout field
+func (c *Client) GetTimeout() time.Duration {
+	return c.timeout
+}
+
+// GetHeaders returns the headers field
+func (c *Client) GetHeaders() map[string]string {
+	return c.headers
+}
+
+// GetPlugins returns the plugins field
+func (c *Client) GetPlugins() []string {
+	return c.plugins
+}
+
+// GetAliases returns the aliases field
+func (c *Client) GetAliases() []string {
+	return c.aliases
+}
@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientBuilderCollections(t *testing.T) {
+	client := NewClientBuilder().
+		BaseURL("https://example.com").
+		PutHeader("Accept", "application/json").
+		PutHeader("User-Agent", "example").
+		AddPlugin("retry").
+		AddPlugins("metrics", "tracing").
+		AddAlias("api").
+		Build()
+
+	expectedHeaders := map[string]string{"Accept": "application/json", "User-Agent": "example"}
+	if !reflect.DeepEqual(client.GetHeaders(), expectedHeaders) {
+		t.Errorf("GetHeaders() = %v, want %v", client.GetHeaders(), expectedHeaders)
+	}
+
+	expectedPlugins := []string{"retry", "metrics", "tracing"}
+	if !reflect.DeepEqual(client.GetPlugins(), expectedPlugins) {
+		t.Errorf("GetPlugins() = %v, want %v", client.GetPlugins(), expectedPlugins)
+	}
+
+	if !reflect.DeepEqual(client.GetAliases(), []string{"api"}) {
+		t.Errorf("GetAliases() = %v, want [api]", client.GetAliases())
+	}
+}
+
+func TestClientToBuilderCollections(t *testing.T) {
+	original := NewClientBuilder().
+		PutHeader("Accept", "application/json").
+		AddPlugins("retry", "metrics").
+		Build()
+
+	dev := original.ToBuilder().PutHeader("Env", "dev").AddPlugin("tracing").Build()
+	prod := original.ToBuilder().PutHeader("Env", "prod").AddPlugin("audit").Build()
+
+	if !reflect.DeepEqual(original.GetHeaders(), map[string]string{"Accept": "application/json"}) {
+		t.Errorf("original GetHeaders() = %v, want only Accept", original.GetHeaders())
+	}
+	if !reflect.DeepEqual(original.GetPlugins(), []string{"retry", "metrics"}) {
+		t.Errorf("original GetPlugins() = %v, want [retry metrics]", original.GetPlugins())
+	}
+
+	if dev.GetHeaders()["Env"] != "dev" || prod.GetHeaders()["Env"] != "prod" {
+		t.Errorf("Env headers = %q, %q, want dev, prod", dev.GetHeaders()["Env"], prod.GetHeaders()["Env"])
+	}
+	if !reflect.DeepEqual(dev.GetPlugins(), []string{"retry", "metrics", "tracing"}) {
+		t.Errorf("dev GetPlugins() = %v, want [retry metrics tracing]", dev.GetPlugins())
+	}
+	if !reflect.DeepEqual(prod.GetPlugins(), []string{"retry", "metrics", "audit"}) {
+		t.Errorf("prod GetPlugins() = %v, want [retry metrics audit]", prod.GetPlugins())
+	}
+}
+
+func TestClientOptionCollections(t *testing.T) {
+	client := NewClientWithOptions(
+		WithPlugins([]string{"retry"}),
+		WithPlugin("metrics"),
+		WithHeader("Accept", "text/plain"),
+		WithAlias("api"),
+	)
+
+	if !reflect.DeepEqual(client.GetPlugins(), []string{"retry", "metrics"}) {
+		t.Errorf("GetPlugins() = %v, want [retry metrics]", client.GetPlugins())
+	}
+
+	if client.GetHeaders()["Accept"] != "text/plain" {
+		t.Errorf("GetHeaders() = %v, want Accept: text/plain", client.GetHeaders())
+	}
+
+	if !reflect.DeepEqual(client.GetAliases(), []string{"api"}) {
+		t.Errorf("GetAliases() = %v, want [api]", client.GetAliases())
+	}
+}
@@ -1,16 +1,17 @@
 package builder
 
-//go:generate go run ../../. -type=Database -constructorTypes=builder -withGetter
+//go:generate go run ../../. -type=Database -constructorTypes=builder -toBuilder -withGetter
 
 // Database represents a database configuration
 // This example demonstrates:
 // 1. Builder pattern with getters
 // 2. Setter-only fields (no getter) with constructor:"getter:false"
 // 3. Getter-only fields (no setter) with constructor:"setter:false"
+// 4. Builders created from existing instances with ToBuilder
 type Database struct {
-	host     string // Will have setter and getter
-	port     int    // Will have setter and getter
-	username string // Will have setter and getter
-	password string `constructor:"getter:false"` // Will have setter but NO getter (security)
-	poolSize int    `constructor:"setter:false"` // Will have getter but NO setter (internal management)
+	host     string // Host name of the database server
+	port     int    // TCP port of the database server
+	username string // User to connect as
+	password string `constructor:"getter:false"` // Password of the user, with no getter
+	poolSize int    `constructor:"setter:false"` // Connections kept open, managed internally
 }
@@ -3,11 +3,19 @@ package builder
 // Code generated by constructor. DO NOT EDIT.
 
 // DatabaseBuilder is a builder for Database
+//
+// Database represents a database configuration
+// This example demonstrates:
+// 1. Builder pattern with getters
+// 2. Setter-only fields (no getter) with constructor:"getter:false"
+// 3. Getter-only fields (no setter) with constructor:"setter:false"
+// 4. Builders created from existing instances with ToBuilder
 type DatabaseBuilder struct {
 	host     string
 	port     int
 	username string
 	password string
+	poolSize int
 }
 
 // NewDatabaseBuilder creates a new DatabaseBuilder
@@ -15,25 +23,49 @@ func NewDatabaseBuilder() *DatabaseBuilder {
 	return &DatabaseBuilder{}
 }
 
+// NewDatabaseBuilderFrom creates a new DatabaseBuilder initialized with the fields of v
+func NewDatabaseBuilderFrom(v *Database) *DatabaseBuilder {
+	return &DatabaseBuilder{
+		host:     v.host,
+		port:     v.port,
+		username: v.username,
+		password: v.password,
+		poolSize: v.poolSize,
+	}
+}
+
+// ToBuilder returns a new DatabaseBuilder initialized with the fields of d
+func (d *Database) ToBuilder() *DatabaseBuilder {
+	return NewDatabaseBuilderFrom(d)
+}
+
 // Host sets the host field
+//
+// Host name of the database server
 func (b *DatabaseBuilder) Host(host string) *DatabaseBuilder {
 	b.host = host
 	return b
 }
 
 // Port sets the port field
+//
+// TCP port of the database server
 func (b *DatabaseBuilder) Port(port int) *DatabaseBuilder {
 	b.port = port
 	return b
 }
 
 // Username sets the username field
+//
+// User to connect as
 func (b *DatabaseBuilder) Username(username string) *DatabaseBuilder {
 	b.username = username
 	return b
 }
 
 // Password sets the password field
+//
+// Password of the user, with no getter
 func (b *DatabaseBuilder) Password(password string) *DatabaseBuilder {
 	b.password = password
 	return b
@@ -46,26 +78,35 @@ func (b *DatabaseBuilder) Build() *Database {
 		port:     b.port,
 		username: b.username,
 		password: b.password,
+		poolSize: b.poolSize,
 	}
 	return v
 }
 
 // GetHost returns the host field
+//
+// Host name of the database server
 func (d *Database) GetHost() string {
 	return d.host
 }
 
 // GetPort returns the port field
+//
+// TCP port of the database server
 func (d *Database) GetPort() int {
 	return d.port
 }
 
 // GetUsername returns the username field
+//
+// User to connect as
 func (d *Database) GetUsername() string {
 	return d.username
 }
 
 // GetPoolSize returns the poolSize field
+//
+// Connections kept open, managed internally
 func (d *Database) GetPoolSize() int {
 	return d.poolSize
 }
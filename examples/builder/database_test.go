@@ -77,3 +77,32 @@ func TestDatabaseSetterSkipping(t *testing.T) {
 		t.Error("PoolSize() setter should not exist due to constructor:\"setter:false\" tag")
 	}
 }
+
+func TestDatabaseToBuilder(t *testing.T) {
+	db := NewDatabaseBuilder().
+		Host("localhost").
+		Port(5432).
+		Username("admin").
+		Build()
+	db.poolSize = 10
+
+	updated := db.ToBuilder().Port(5433).Build()
+
+	if updated.GetHost() != "localhost" || updated.GetUsername() != "admin" {
+		t.Errorf("Expected unchanged fields to be copied, got %s %s", updated.GetHost(), updated.GetUsername())
+	}
+
+	if updated.GetPort() != 5433 {
+		t.Errorf("Expected port 5433, got %d", updated.GetPort())
+	}
+
+	// Fields without setters are carried over instead of reset
+	if updated.GetPoolSize() != 10 {
+		t.Errorf("Expected poolSize 10, got %d", updated.GetPoolSize())
+	}
+
+	// The original is left unchanged
+	if db.GetPort() != 5432 {
+		t.Errorf("Expected original port 5432, got %d", db.GetPort())
+	}
+}
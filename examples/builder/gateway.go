@@ -0,0 +1,30 @@
+package builder
+
+import "time"
+
+//go:generate go run ../../. -type=Gateway,TLSConfig,RateLimit -constructorTypes=builder -nestedBuilders -output=gateway_gen.go
+
+// Gateway represents an API gateway configuration tree
+// This example demonstrates:
+// 1. Nested builders: ConfigureTls and ConfigureRateLimit build struct fields in place
+// 2. Pointer and value fields, and builders returning pointers or values
+// 3. Several structs generated into one file with -output
+type Gateway struct {
+	listen    string
+	tls       *TLSConfig
+	rateLimit RateLimit
+}
+
+// TLSConfig holds the certificate files of a gateway
+type TLSConfig struct {
+	certFile string
+	keyFile  string
+}
+
+// RateLimit limits the requests per client
+//
+//constructor:gen builder returnValue
+type RateLimit struct {
+	requests int
+	window   time.Duration
+}
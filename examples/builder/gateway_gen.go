@@ -0,0 +1,135 @@
+package builder
+
+import "time"
+
+// Code generated by constructor. DO NOT EDIT.
+
+// GatewayBuilder is a builder for Gateway
+//
+// Gateway represents an API gateway configuration tree
+// This example demonstrates:
+// 1. Nested builders: ConfigureTls and ConfigureRateLimit build struct fields in place
+// 2. Pointer and value fields, and builders returning pointers or values
+// 3. Several structs generated into one file with -output
+type GatewayBuilder struct {
+	listen    string
+	tls       *TLSConfig
+	rateLimit RateLimit
+}
+
+// NewGatewayBuilder creates a new GatewayBuilder
+func NewGatewayBuilder() *GatewayBuilder {
+	return &GatewayBuilder{}
+}
+
+// Listen sets the listen field
+func (b *GatewayBuilder) Listen(listen string) *GatewayBuilder {
+	b.listen = listen
+	return b
+}
+
+// Tls sets the tls field
+func (b *GatewayBuilder) Tls(tls *TLSConfig) *GatewayBuilder {
+	b.tls = tls
+	return b
+}
+
+// RateLimit sets the rateLimit field
+func (b *GatewayBuilder) RateLimit(rateLimit RateLimit) *GatewayBuilder {
+	b.rateLimit = rateLimit
+	return b
+}
+
+// ConfigureTls sets the tls field to a TLSConfig built by configure
+func (b *GatewayBuilder) ConfigureTls(configure func(*TLSConfigBuilder)) *GatewayBuilder {
+	nested := NewTLSConfigBuilder()
+	configure(nested)
+	b.tls = nested.Build()
+	return b
+}
+
+// ConfigureRateLimit sets the rateLimit field to a RateLimit built by configure
+func (b *GatewayBuilder) ConfigureRateLimit(configure func(*RateLimitBuilder)) *GatewayBuilder {
+	nested := NewRateLimitBuilder()
+	configure(nested)
+	b.rateLimit = nested.Build()
+	return b
+}
+
+// Build builds the Gateway
+func (b *GatewayBuilder) Build() *Gateway {
+	v := &Gateway{
+		listen:    b.listen,
+		tls:       b.tls,
+		rateLimit: b.rateLimit,
+	}
+	return v
+}
+
+// TLSConfigBuilder is a builder for TLSConfig
+//
+// TLSConfig holds the certificate files of a gateway
+type TLSConfigBuilder struct {
+	certFile string
+	keyFile  string
+}
+
+// NewTLSConfigBuilder creates a new TLSConfigBuilder
+func NewTLSConfigBuilder() *TLSConfigBuilder {
+	return &TLSConfigBuilder{}
+}
+
+// CertFile sets the certFile field
+func (b *TLSConfigBuilder) CertFile(certFile string) *TLSConfigBuilder {
+	b.certFile = certFile
+	return b
+}
+
+// KeyFile sets the keyFile field
+func (b *TLSConfigBuilder) KeyFile(keyFile string) *TLSConfigBuilder {
+	b.keyFile = keyFile
+	return b
+}
+
+// Build builds the TLSConfig
+func (b *TLSConfigBuilder) Build() *TLSConfig {
+	v := &TLSConfig{
+		certFile: b.certFile,
+		keyFile:  b.keyFile,
+	}
+	return v
+}
+
+// RateLimitBuilder is a builder for RateLimit
+//
+// RateLimit limits the requests per client
+type RateLimitBuilder struct {
+	requests int
+	window   time.Duration
+}
+
+// NewRateLimitBuilder creates a new RateLimitBuilder
+func NewRateLimitBuilder() *RateLimitBuilder {
+	return &RateLimitBuilder{}
+}
+
+// Requests sets the requests field
+func (b *RateLimitBuilder) Requests(requests int) *RateLimitBuilder {
+	b.requests = requests
+	return b
+}
+
+// Window sets the window field
+func (b *RateLimitBuilder) Window(window time.Duration) *RateLimitBuilder {
+	b.window = window
+	return b
+}
+
+// Build builds the RateLimit
+func (b *RateLimitBuilder) Build() RateLimit {
+	v := RateLimit{
+		requests: b.requests,
+		window:   b.window,
+	}
+	return v
+}
@@ -0,0 +1,26 @@
+package builder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGatewayNestedBuilders(t *testing.T) {
+	gateway := NewGatewayBuilder().
+		Listen(":443").
+		ConfigureTls(func(b *TLSConfigBuilder) {
+			b.CertFile("cert.pem").KeyFile("key.pem")
+		}).
+		ConfigureRateLimit(func(b *RateLimitBuilder) {
+			b.Requests(100).Window(time.Minute)
+		}).
+		Build()
+
+	if gateway.tls == nil || gateway.tls.certFile != "cert.pem" || gateway.tls.keyFile != "key.pem" {
+		t.Errorf("Expected TLS config with cert.pem and key.pem, got %+v", gateway.tls)
+	}
+
+	if gateway.rateLimit.requests != 100 || gateway.rateLimit.window != time.Minute {
+		t.Errorf("Expected 100 requests per minute, got %+v", gateway.rateLimit)
+	}
+}
@@ -1,29 +1,29 @@
 package builder
 
-import "time"
+import (
+	"net"
+	"strconv"
+	"time"
+)
 
 //go:generate go run ../../. -type=Service -constructorTypes=builder -setterPrefix=With -init=initialize
 
 // Service represents a service configuration
 // This example demonstrates:
 // 1. Builder pattern with "With" prefix for setters
-// 2. Init function support
-// 3. Field skipping in builder
+// 2. Default values with constructor:"default=..."
+// 3. Init function support
+// 4. Field skipping in builder
 type Service struct {
 	name       string
 	host       string
 	port       int
-	timeout    time.Duration
-	maxRetries int
-	internal   string `constructor:"-"` // Completely skipped - no setter method
+	timeout    time.Duration `constructor:"default=30s"`
+	maxRetries int           `constructor:"default=3"`
+	internal   string        `constructor:"-"` // Completely skipped - no setter method
 }
 
 // initialize is called after the service is built
 func (s *Service) initialize() {
-	if s.timeout == 0 {
-		s.timeout = 30 * time.Second
-	}
-	if s.maxRetries == 0 {
-		s.maxRetries = 3
-	}
+	s.internal = net.JoinHostPort(s.host, strconv.Itoa(s.port))
 }
@@ -5,6 +5,13 @@ import "time"
 // Code generated by constructor. DO NOT EDIT.
 
 // ServiceBuilder is a builder for Service
+//
+// Service represents a service configuration
+// This example demonstrates:
+// 1. Builder pattern with "With" prefix for setters
+// 2. Default values with constructor:"default=..."
+// 3. Init function support
+// 4. Field skipping in builder
 type ServiceBuilder struct {
 	name       string
 	host       string
@@ -15,7 +22,10 @@ type ServiceBuilder struct {
 
 // NewServiceBuilder creates a new ServiceBuilder
 func NewServiceBuilder() *ServiceBuilder {
-	return &ServiceBuilder{}
+	return &ServiceBuilder{
+		timeout:    30 * time.Second,
+		maxRetries: 3,
+	}
 }
 
 // WithName sets the name field
@@ -40,7 +40,7 @@ func TestServiceBuilder(t *testing.T) {
 	}
 }
 
-func TestServiceInitFunction(t *testing.T) {
+func TestServiceDefaults(t *testing.T) {
 	// Build service without setting timeout and maxRetries
 	service := NewServiceBuilder().
 		WithName("Test Service").
@@ -48,7 +48,7 @@ func TestServiceInitFunction(t *testing.T) {
 		WithPort(443).
 		Build()
 
-	// Verify that initialize() was called and set defaults
+	// Verify that the tag defaults were applied
 	if service.timeout != 30*time.Second {
 		t.Errorf("Expected default timeout 30s, got %v", service.timeout)
 	}
@@ -56,6 +56,24 @@ func TestServiceInitFunction(t *testing.T) {
 	if service.maxRetries != 3 {
 		t.Errorf("Expected default maxRetries 3, got %d", service.maxRetries)
 	}
+
+	// An explicit zero overrides the default
+	service = NewServiceBuilder().WithMaxRetries(0).Build()
+	if service.maxRetries != 0 {
+		t.Errorf("Expected explicit maxRetries 0, got %d", service.maxRetries)
+	}
+}
+
+func TestServiceInitFunction(t *testing.T) {
+	service := NewServiceBuilder().
+		WithHost("example.com").
+		WithPort(443).
+		Build()
+
+	// Verify that initialize() was called
+	if service.internal != "example.com:443" {
+		t.Errorf("Expected internal example.com:443, got %s", service.internal)
+	}
 }
 
 func TestServiceFieldSkipping(t *testing.T) {
@@ -0,0 +1,18 @@
+package generics
+
+import "time"
+
+//go:generate go run ../../. -type=Cache -constructorTypes=allArgs,builder,options -withGetter
+
+// Cache represents a generic in-memory cache
+// This example demonstrates:
+// 1. Generic structs with type parameters
+// 2. Type parameters propagated to builder, option and getter types
+// 3. Function-typed fields rendered exactly as declared
+type Cache[K comparable, V any] struct {
+	items    map[K]V
+	capacity int
+	ttl      time.Duration
+	keys     []K
+	onEvict  func(key K, value V)
+}
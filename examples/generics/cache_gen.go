@@ -0,0 +1,168 @@
+package generics
+
+import "time"
+
+// Code generated by constructor. DO NOT EDIT.
+
+// NewCache creates a new Cache
+//
+// Cache represents a generic in-memory cache
+// This example demonstrates:
+// 1. Generic structs with type parameters
+// 2. Type parameters propagated to builder, option and getter types
+// 3. Function-typed fields rendered exactly as declared
+func NewCache[K comparable, V any](items map[K]V, capacity int, ttl time.Duration, keys []K, onEvict func(key K, value V)) *Cache[K, V] {
+	return &Cache[K, V]{
+		items:    items,
+		capacity: capacity,
+		ttl:      ttl,
+		keys:     keys,
+		onEvict:  onEvict,
+	}
+}
+
+// CacheBuilder is a builder for Cache
+//
+// Cache represents a generic in-memory cache
+// This example demonstrates:
+// 1. Generic structs with type parameters
+// 2. Type parameters propagated to builder, option and getter types
+// 3. Function-typed fields rendered exactly as declared
+type CacheBuilder[K comparable, V any] struct {
+	items    map[K]V
+	capacity int
+	ttl      time.Duration
+	keys     []K
+	onEvict  func(key K, value V)
+}
+
+// NewCacheBuilder creates a new CacheBuilder
+func NewCacheBuilder[K comparable, V any]() *CacheBuilder[K, V] {
+	return &CacheBuilder[K, V]{}
+}
+
+// Items sets the items field
+func (b *CacheBuilder[K, V]) Items(items map[K]V) *CacheBuilder[K, V] {
+	b.items = items
+	return b
+}
+
+// Capacity sets the capacity field
+func (b *CacheBuilder[K, V]) Capacity(capacity int) *CacheBuilder[K, V] {
+	b.capacity = capacity
+	return b
+}
+
+// Ttl sets the ttl field
+func (b *CacheBuilder[K, V]) Ttl(ttl time.Duration) *CacheBuilder[K, V] {
+	b.ttl = ttl
+	return b
+}
+
+// Keys sets the keys field
+func (b *CacheBuilder[K, V]) Keys(keys []K) *CacheBuilder[K, V] {
+	b.keys = keys
+	return b
+}
+
+// OnEvict sets the onEvict field
+func (b *CacheBuilder[K, V]) OnEvict(onEvict func(key K, value V)) *CacheBuilder[K, V] {
+	b.onEvict = onEvict
+	return b
+}
+
+// Build builds the Cache
+func (b *CacheBuilder[K, V]) Build() *Cache[K, V] {
+	v := &Cache[K, V]{
+		items:    b.items,
+		capacity: b.capacity,
+		ttl:      b.ttl,
+		keys:     b.keys,
+		onEvict:  b.onEvict,
+	}
+	return v
+}
+
+// CacheOption is a functional option for configuring Cache
+//
+// Cache represents a generic in-memory cache
+// This example demonstrates:
+// 1. Generic structs with type parameters
+// 2. Type parameters propagated to builder, option and getter types
+// 3. Function-typed fields rendered exactly as declared
+type CacheOption[K comparable, V any] func(*Cache[K, V])
+
+// WithItems sets the items field
+func WithItems[K comparable, V any](items map[K]V) CacheOption[K, V] {
+	return func(s *Cache[K, V]) {
+		s.items = items
+	}
+}
+
+// WithCapacity sets the capacity field
+func WithCapacity[K comparable, V any](capacity int) CacheOption[K, V] {
+	return func(s *Cache[K, V]) {
+		s.capacity = capacity
+	}
+}
+
+// WithTtl sets the ttl field
+func WithTtl[K comparable, V any](ttl time.Duration) CacheOption[K, V] {
+	return func(s *Cache[K, V]) {
+		s.ttl = ttl
+	}
+}
+
+// WithKeys sets the keys field
+func WithKeys[K comparable, V any](keys []K) CacheOption[K, V] {
+	return func(s *Cache[K, V]) {
+		s.keys = keys
+	}
+}
+
+// WithOnEvict sets the onEvict field
+func WithOnEvict[K comparable, V any](onEvict func(key K, value V)) CacheOption[K, V] {
+	return func(s *Cache[K, V]) {
+		s.onEvict = onEvict
+	}
+}
+
+// NewCacheWithOptions creates a new Cache with functional options
+//
+// Cache represents a generic in-memory cache
+// This example demonstrates:
+// 1. Generic structs with type parameters
+// 2. Type parameters propagated to builder, option and getter types
+// 3. Function-typed fields rendered exactly as declared
+func NewCacheWithOptions[K comparable, V any](opts ...CacheOption[K, V]) *Cache[K, V] {
+	v := &Cache[K, V]{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// GetItems returns the items field
+func (c *Cache[K, V]) GetItems() map[K]V {
+	return c.items
+}
+
+// GetCapacity returns the capacity field
+func (c *Cache[K, V]) GetCapacity() int {
+	return c.capacity
+}
+
+// GetTtl returns the ttl field
+func (c *Cache[K, V]) GetTtl() time.Duration {
+	return c.ttl
+}
+
+// GetKeys returns the keys field
+func (c *Cache[K, V]) GetKeys() []K {
+	return c.keys
+}
+
+// GetOnEvict returns the onEvict field
+func (c *Cache[K, V]) GetOnEvict() func(key K, value V) {
+	return c.onEvict
+}
@@ -0,0 +1,56 @@
+package generics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCache(t *testing.T) {
+	cache := NewCache(map[string]int{"a": 1}, 10, time.Minute, []string{"a"}, nil)
+
+	if cache == nil {
+		t.Fatal("NewCache returned nil")
+	}
+
+	if cache.items["a"] != 1 {
+		t.Errorf("Expected items[a] 1, got %d", cache.items["a"])
+	}
+
+	if cache.capacity != 10 {
+		t.Errorf("Expected capacity 10, got %d", cache.capacity)
+	}
+
+	if cache.ttl != time.Minute {
+		t.Errorf("Expected ttl 1m, got %v", cache.ttl)
+	}
+}
+
+func TestCacheBuilder(t *testing.T) {
+	cache := NewCacheBuilder[string, int]().
+		Items(map[string]int{"a": 1}).
+		Capacity(20).
+		Build()
+
+	if cache.capacity != 20 {
+		t.Errorf("Expected capacity 20, got %d", cache.capacity)
+	}
+
+	if cache.GetItems()["a"] != 1 {
+		t.Errorf("Expected items[a] 1, got %d", cache.GetItems()["a"])
+	}
+}
+
+func TestCacheWithOptions(t *testing.T) {
+	cache := NewCacheWithOptions(
+		WithCapacity[string, int](30),
+		WithTtl[string, int](time.Second),
+	)
+
+	if cache.GetCapacity() != 30 {
+		t.Errorf("Expected capacity 30, got %d", cache.GetCapacity())
+	}
+
+	if cache.GetTtl() != time.Second {
+		t.Errorf("Expected ttl 1s, got %v", cache.GetTtl())
+	}
+}
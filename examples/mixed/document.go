@@ -0,0 +1,23 @@
+package mixed
+
+import "time"
+
+//go:generate go run ../../. -type=Document -output=document_gen.go -constructorTypes=allArgs,builder -withGetter
+
+// BaseModel holds the fields shared by stored records
+type BaseModel struct {
+	Owner     string
+	createdAt time.Time
+	revision  int `constructor:"default=1,setter:false"`
+}
+
+// Document represents a stored document
+// This example demonstrates:
+// 1. Flattening an embedded struct into the constructors with constructor:"flatten"
+// 2. Embedded fields named after their type, e.g., Document.BaseModel
+type Document struct {
+	BaseModel `constructor:"flatten"`
+
+	title string `constructor:"required"`
+	body  string
+}
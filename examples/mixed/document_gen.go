@@ -0,0 +1,112 @@
+package mixed
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Code generated by constructor. DO NOT EDIT.
+
+// NewDocument creates a new Document
+//
+// Document represents a stored document
+// This example demonstrates:
+// 1. Flattening an embedded struct into the constructors with constructor:"flatten"
+// 2. Embedded fields named after their type, e.g., Document.BaseModel
+func NewDocument(owner string, createdAt time.Time, title string, body string) *Document {
+	return &Document{
+		BaseModel: BaseModel{
+			Owner:     owner,
+			createdAt: createdAt,
+			revision:  1,
+		},
+		title: title,
+		body:  body,
+	}
+}
+
+// DocumentBuilder is a builder for Document
+//
+// Document represents a stored document
+// This example demonstrates:
+// 1. Flattening an embedded struct into the constructors with constructor:"flatten"
+// 2. Embedded fields named after their type, e.g., Document.BaseModel
+type DocumentBuilder struct {
+	owner     string
+	createdAt time.Time
+	title     string
+	body      string
+	titleSet  bool
+}
+
+// NewDocumentBuilder creates a new DocumentBuilder
+func NewDocumentBuilder() *DocumentBuilder {
+	return &DocumentBuilder{}
+}
+
+// Owner sets the Owner field
+func (b *DocumentBuilder) Owner(owner string) *DocumentBuilder {
+	b.owner = owner
+	return b
+}
+
+// CreatedAt sets the createdAt field
+func (b *DocumentBuilder) CreatedAt(createdAt time.Time) *DocumentBuilder {
+	b.createdAt = createdAt
+	return b
+}
+
+// Title sets the title field
+func (b *DocumentBuilder) Title(title string) *DocumentBuilder {
+	b.title = title
+	b.titleSet = true
+	return b
+}
+
+// Body sets the body field
+func (b *DocumentBuilder) Body(body string) *DocumentBuilder {
+	b.body = body
+	return b
+}
+
+// Build builds the Document, returning an error if a required field was not set
+func (b *DocumentBuilder) Build() (*Document, error) {
+	var missing []string
+	if !b.titleSet {
+		missing = append(missing, "title")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required fields of Document: %s", strings.Join(missing, ", "))
+	}
+	v := &Document{
+		BaseModel: BaseModel{
+			Owner:     b.owner,
+			createdAt: b.createdAt,
+			revision:  1,
+		},
+		title: b.title,
+		body:  b.body,
+	}
+	return v, nil
+}
+
+// GetCreatedAt returns the createdAt field
+func (d *Document) GetCreatedAt() time.Time {
+	return d.BaseModel.createdAt
+}
+
+// GetRevision returns the revision field
+func (d *Document) GetRevision() int {
+	return d.BaseModel.revision
+}
+
+// GetTitle returns the title field
+func (d *Document) GetTitle() string {
+	return d.title
+}
+
+// GetBody returns the body field
+func (d *Document) GetBody() string {
+	return d.body
+}
@@ -0,0 +1,42 @@
+package mixed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDocumentFlatten(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc := NewDocument("alice", created, "Notes", "Hello")
+
+	// Flattened fields are set through the embedded BaseModel
+	if doc.Owner != "alice" || doc.BaseModel.Owner != "alice" {
+		t.Errorf("Expected owner 'alice', got %q", doc.Owner)
+	}
+	if !doc.GetCreatedAt().Equal(created) {
+		t.Errorf("Expected createdAt %v, got %v", created, doc.GetCreatedAt())
+	}
+	if doc.GetRevision() != 1 {
+		t.Errorf("Expected default revision 1, got %d", doc.GetRevision())
+	}
+	if doc.GetTitle() != "Notes" {
+		t.Errorf("Expected title 'Notes', got %q", doc.GetTitle())
+	}
+}
+
+func TestDocumentBuilderFlatten(t *testing.T) {
+	doc, err := NewDocumentBuilder().
+		Owner("bob").
+		Title("Draft").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if doc.Owner != "bob" || doc.GetRevision() != 1 {
+		t.Errorf("Expected owner 'bob' and revision 1, got %q and %d", doc.Owner, doc.GetRevision())
+	}
+
+	if _, err := NewDocumentBuilder().Owner("bob").Build(); err == nil {
+		t.Error("Expected error for missing title")
+	}
+}
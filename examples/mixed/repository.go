@@ -9,16 +9,18 @@ import "time"
 // 1. Multiple constructor patterns in one file
 // 2. All three patterns: allArgs, builder, and options
 // 3. Comprehensive field skipping examples
+// 4. Required fields checked by Build and NewRepositoryWithOptions
 type Repository struct {
-	// Fields included in all constructors with getters
-	dsn         string
-	maxConns    int
-	idleTimeout time.Duration
+	// Data source name; Build and NewRepositoryWithOptions return an error without it
+	dsn string `constructor:"required"`
 
-	// Skip getter (will be in constructors but no getter)
+	maxConns    int           // Maximum number of open connections
+	idleTimeout time.Duration // Time after which idle connections are closed
+
+	// Password of the database user, in constructors but with no getter
 	password string `constructor:"getter:false"`
 
-	// Skip setter (will have getter but not in constructors)
+	// Number of open connections, with a getter but not in constructors
 	connCount int `constructor:"setter:false"`
 
 	// Completely skip
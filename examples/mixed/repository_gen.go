@@ -1,10 +1,27 @@
 package mixed
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Code generated by constructor. DO NOT EDIT.
 
 // NewRepository creates a new Repository
+//
+// Repository represents a data repository
+// This example demonstrates:
+// 1. Multiple constructor patterns in one file
+// 2. All three patterns: allArgs, builder, and options
+// 3. Comprehensive field skipping examples
+// 4. Required fields checked by Build and NewRepositoryWithOptions
+//
+// Parameters:
+//   - dsn: Data source name; Build and NewRepositoryWithOptions return an error without it
+//   - maxConns: Maximum number of open connections
+//   - idleTimeout: Time after which idle connections are closed
+//   - password: Password of the database user, in constructors but with no getter
 func NewRepository(dsn string, maxConns int, idleTimeout time.Duration, password string) *Repository {
 	return &Repository{
 		dsn:         dsn,
@@ -15,11 +32,19 @@ func NewRepository(dsn string, maxConns int, idleTimeout time.Duration, password
 }
 
 // RepositoryBuilder is a builder for Repository
+//
+// Repository represents a data repository
+// This example demonstrates:
+// 1. Multiple constructor patterns in one file
+// 2. All three patterns: allArgs, builder, and options
+// 3. Comprehensive field skipping examples
+// 4. Required fields checked by Build and NewRepositoryWithOptions
 type RepositoryBuilder struct {
 	dsn         string
 	maxConns    int
 	idleTimeout time.Duration
 	password    string
+	dsnSet      bool
 }
 
 // NewRepositoryBuilder creates a new RepositoryBuilder
@@ -28,44 +53,69 @@ func NewRepositoryBuilder() *RepositoryBuilder {
 }
 
 // Dsn sets the dsn field
+//
+// Data source name; Build and NewRepositoryWithOptions return an error without it
 func (b *RepositoryBuilder) Dsn(dsn string) *RepositoryBuilder {
 	b.dsn = dsn
+	b.dsnSet = true
 	return b
 }
 
 // MaxConns sets the maxConns field
+//
+// Maximum number of open connections
 func (b *RepositoryBuilder) MaxConns(maxConns int) *RepositoryBuilder {
 	b.maxConns = maxConns
 	return b
 }
 
 // IdleTimeout sets the idleTimeout field
+//
+// Time after which idle connections are closed
 func (b *RepositoryBuilder) IdleTimeout(idleTimeout time.Duration) *RepositoryBuilder {
 	b.idleTimeout = idleTimeout
 	return b
 }
 
 // Password sets the password field
+//
+// Password of the database user, in constructors but with no getter
 func (b *RepositoryBuilder) Password(password string) *RepositoryBuilder {
 	b.password = password
 	return b
 }
 
-// Build builds the Repository
-func (b *RepositoryBuilder) Build() *Repository {
+// Build builds the Repository, returning an error if a required field was not set
+func (b *RepositoryBuilder) Build() (*Repository, error) {
+	var missing []string
+	if !b.dsnSet {
+		missing = append(missing, "dsn")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required fields of Repository: %s", strings.Join(missing, ", "))
+	}
 	v := &Repository{
 		dsn:         b.dsn,
 		maxConns:    b.maxConns,
 		idleTimeout: b.idleTimeout,
 		password:    b.password,
 	}
-	return v
+	return v, nil
 }
 
 // RepositoryOption is a functional option for configuring Repository
+//
+// Repository represents a data repository
+// This example demonstrates:
+// 1. Multiple constructor patterns in one file
+// 2. All three patterns: allArgs, builder, and options
+// 3. Comprehensive field skipping examples
+// 4. Required fields checked by Build and NewRepositoryWithOptions
 type RepositoryOption func(*Repository)
 
 // WithDsn sets the dsn field
+//
+// Data source name; Build and NewRepositoryWithOptions return an error without it
 func WithDsn(dsn string) RepositoryOption {
 	return func(s *Repository) {
 		s.dsn = dsn
@@ -73,6 +123,8 @@ func WithDsn(dsn string) RepositoryOption {
 }
 
 // WithMaxConns sets the maxConns field
+//
+// Maximum number of open connections
 func WithMaxConns(maxConns int) RepositoryOption {
 	return func(s *Repository) {
 		s.maxConns = maxConns
@@ -80,6 +132,8 @@ func WithMaxConns(maxConns int) RepositoryOption {
 }
 
 // WithIdleTimeout sets the idleTimeout field
+//
+// Time after which idle connections are closed
 func WithIdleTimeout(idleTimeout time.Duration) RepositoryOption {
 	return func(s *Repository) {
 		s.idleTimeout = idleTimeout
@@ -87,37 +141,62 @@ func WithIdleTimeout(idleTimeout time.Duration) RepositoryOption {
 }
 
 // WithPassword sets the password field
+//
+// Password of the database user, in constructors but with no getter
 func WithPassword(password string) RepositoryOption {
 	return func(s *Repository) {
 		s.password = password
 	}
 }
 
-// NewRepositoryWithOptions creates a new Repository with functional options
-func NewRepositoryWithOptions(opts ...RepositoryOption) *Repository {
+// NewRepositoryWithOptions creates a new Repository with functional options,
+// returning an error if a required field was left at its zero value
+//
+// Repository represents a data repository
+// This example demonstrates:
+// 1. Multiple constructor patterns in one file
+// 2. All three patterns: allArgs, builder, and options
+// 3. Comprehensive field skipping examples
+// 4. Required fields checked by Build and NewRepositoryWithOptions
+func NewRepositoryWithOptions(opts ...RepositoryOption) (*Repository, error) {
 	v := &Repository{}
 	for _, opt := range opts {
 		opt(v)
 	}
-	return v
+	var missing []string
+	if v.dsn == "" {
+		missing = append(missing, "dsn")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required fields of Repository: %s", strings.Join(missing, ", "))
+	}
+	return v, nil
 }
 
 // GetDsn returns the dsn field
+//
+// Data source name; Build and NewRepositoryWithOptions return an error without it
 func (r *Repository) GetDsn() string {
 	return r.dsn
 }
 
 // GetMaxConns returns the maxConns field
+//
+// Maximum number of open connections
 func (r *Repository) GetMaxConns() int {
 	return r.maxConns
 }
 
 // GetIdleTimeout returns the idleTimeout field
+//
+// Time after which idle connections are closed
 func (r *Repository) GetIdleTimeout() time.Duration {
 	return r.idleTimeout
 }
 
 // GetConnCount returns the connCount field
+//
+// Number of open connections, with a getter but not in constructors
 func (r *Repository) GetConnCount() int {
 	return r.connCount
 }
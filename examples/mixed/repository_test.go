@@ -36,15 +36,15 @@ func TestNewRepository(t *testing.T) {
 }
 
 func TestRepositoryBuilder(t *testing.T) {
-	repo := NewRepositoryBuilder().
+	repo, err := NewRepositoryBuilder().
 		Dsn("mysql://localhost:3306/db").
 		MaxConns(50).
 		IdleTimeout(10 * time.Minute).
 		Password("secret").
 		Build()
 
-	if repo == nil {
-		t.Fatal("Build returned nil")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
 	}
 
 	if repo.dsn != "mysql://localhost:3306/db" {
@@ -65,15 +65,15 @@ func TestRepositoryBuilder(t *testing.T) {
 }
 
 func TestRepositoryWithOptions(t *testing.T) {
-	repo := NewRepositoryWithOptions(
+	repo, err := NewRepositoryWithOptions(
 		WithDsn("sqlite://data.db"),
 		WithMaxConns(20),
 		WithIdleTimeout(2*time.Minute),
 		WithPassword("pass"),
 	)
 
-	if repo == nil {
-		t.Fatal("NewRepositoryWithOptions returned nil")
+	if err != nil {
+		t.Fatalf("NewRepositoryWithOptions failed: %v", err)
 	}
 
 	if repo.dsn != "sqlite://data.db" {
@@ -93,6 +93,23 @@ func TestRepositoryWithOptions(t *testing.T) {
 	}
 }
 
+func TestRepositoryRequiredFields(t *testing.T) {
+	_, err := NewRepositoryBuilder().MaxConns(50).Build()
+	if err == nil || err.Error() != "missing required fields of Repository: dsn" {
+		t.Errorf("Build without Dsn: expected missing field error, got %v", err)
+	}
+
+	// Setting a required field counts even if the value is zero
+	if _, err := NewRepositoryBuilder().Dsn("").Build(); err != nil {
+		t.Errorf("Build with empty Dsn failed: %v", err)
+	}
+
+	_, err = NewRepositoryWithOptions(WithMaxConns(50))
+	if err == nil || err.Error() != "missing required fields of Repository: dsn" {
+		t.Errorf("NewRepositoryWithOptions without WithDsn: expected missing field error, got %v", err)
+	}
+}
+
 func TestRepositoryGetters(t *testing.T) {
 	repo := NewRepository("dsn", 10, time.Minute, "pwd")
 
@@ -1,10 +1,20 @@
 package options
 
-import "time"
+import (
+	"runtime"
+	"time"
+)
 
 // Code generated by constructor. DO NOT EDIT.
 
 // AppConfigOption is a functional option for configuring AppConfig
+//
+// AppConfig represents application configuration
+// This example demonstrates:
+// 1. Functional options pattern
+// 2. Return value instead of pointer
+// 3. Field skipping with options pattern
+// 4. Default values, including Go expressions, applied before the options
 type AppConfigOption func(*AppConfig)
 
 // WithAppName sets the appName field
@@ -43,6 +53,8 @@ func WithMaxWorkers(maxWorkers int) AppConfigOption {
 }
 
 // WithCacheDir sets the cacheDir field
+//
+// Directory of the local cache
 func WithCacheDir(cacheDir string) AppConfigOption {
 	return func(s *AppConfig) {
 		s.cacheDir = cacheDir
@@ -50,8 +62,18 @@ func WithCacheDir(cacheDir string) AppConfigOption {
 }
 
 // NewAppConfigWithOptions creates a new AppConfig with functional options
+//
+// AppConfig represents application configuration
+// This example demonstrates:
+// 1. Functional options pattern
+// 2. Return value instead of pointer
+// 3. Field skipping with options pattern
+// 4. Default values, including Go expressions, applied before the options
 func NewAppConfigWithOptions(opts ...AppConfigOption) AppConfig {
-	v := &AppConfig{}
+	v := &AppConfig{
+		timeout:    10 * time.Second,
+		maxWorkers: runtime.NumCPU(),
+	}
 	for _, opt := range opts {
 		opt(v)
 	}
@@ -9,12 +9,13 @@ import "time"
 // 1. Functional options pattern
 // 2. Return value instead of pointer
 // 3. Field skipping with options pattern
+// 4. Default values, including Go expressions, applied before the options
 type AppConfig struct {
 	appName    string
 	version    string
 	debug      bool
-	timeout    time.Duration
-	maxWorkers int
-	internal   string `constructor:"-"` // Completely skipped - no With option
-	cacheDir   string // Will have With option
+	timeout    time.Duration `constructor:"default=10s"`
+	maxWorkers int           `constructor:"default=expr:runtime.NumCPU()"`
+	internal   string        `constructor:"-"` // Completely skipped - no With option
+	cacheDir   string        // Directory of the local cache
 }
@@ -2,6 +2,7 @@ package options
 
 import (
 	"reflect"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -83,3 +84,21 @@ func TestAppConfigPartialOptions(t *testing.T) {
 		t.Errorf("Expected empty version, got %s", config.version)
 	}
 }
+
+func TestAppConfigDefaults(t *testing.T) {
+	config := NewAppConfigWithOptions()
+
+	if config.timeout != 10*time.Second {
+		t.Errorf("Expected default timeout 10s, got %v", config.timeout)
+	}
+
+	if config.maxWorkers != runtime.NumCPU() {
+		t.Errorf("Expected default maxWorkers %d, got %d", runtime.NumCPU(), config.maxWorkers)
+	}
+
+	// Options override the defaults
+	config = NewAppConfigWithOptions(WithTimeout(0))
+	if config.timeout != 0 {
+		t.Errorf("Expected timeout 0, got %v", config.timeout)
+	}
+}
@@ -0,0 +1,16 @@
+package options
+
+import "io"
+
+//go:generate go run ../../. -type=Logger -constructorTypes=options -optionStyle=interface -withGetter
+
+// Logger represents a leveled logger
+// This example demonstrates:
+// 1. Interface-based options with an unexported apply method (grpc-go style)
+// 2. Options that can be compared and printed
+// 3. Custom options implemented through the LoggerOptionFunc adapter
+type Logger struct {
+	level  string `constructor:"default=info"`
+	output io.Writer
+	prefix string
+}
@@ -0,0 +1,115 @@
+package options
+
+import (
+	"fmt"
+	"io"
+)
+
+// Code generated by constructor. DO NOT EDIT.
+
+// LoggerOption configures a Logger
+//
+// Logger represents a leveled logger
+// This example demonstrates:
+// 1. Interface-based options with an unexported apply method (grpc-go style)
+// 2. Options that can be compared and printed
+// 3. Custom options implemented through the LoggerOptionFunc adapter
+type LoggerOption interface {
+	apply(*Logger)
+}
+
+// LoggerOptionFunc adapts a function to a LoggerOption
+type LoggerOptionFunc func(*Logger)
+
+func (f LoggerOptionFunc) apply(s *Logger) {
+	f(s)
+}
+
+// loggerLevelOption is the LoggerOption returned by WithLevel
+type loggerLevelOption struct {
+	level string
+}
+
+func (o loggerLevelOption) apply(s *Logger) {
+	s.level = o.level
+}
+
+// String describes the option, e.g., for logging
+func (o loggerLevelOption) String() string {
+	return fmt.Sprintf("WithLevel(%v)", o.level)
+}
+
+// WithLevel sets the level field
+func WithLevel(level string) LoggerOption {
+	return loggerLevelOption{level: level}
+}
+
+// loggerOutputOption is the LoggerOption returned by WithOutput
+type loggerOutputOption struct {
+	output io.Writer
+}
+
+func (o loggerOutputOption) apply(s *Logger) {
+	s.output = o.output
+}
+
+// String describes the option, e.g., for logging
+func (o loggerOutputOption) String() string {
+	return fmt.Sprintf("WithOutput(%v)", o.output)
+}
+
+// WithOutput sets the output field
+func WithOutput(output io.Writer) LoggerOption {
+	return loggerOutputOption{output: output}
+}
+
+// loggerPrefixOption is the LoggerOption returned by WithPrefix
+type loggerPrefixOption struct {
+	prefix string
+}
+
+func (o loggerPrefixOption) apply(s *Logger) {
+	s.prefix = o.prefix
+}
+
+// String describes the option, e.g., for logging
+func (o loggerPrefixOption) String() string {
+	return fmt.Sprintf("WithPrefix(%v)", o.prefix)
+}
+
+// WithPrefix sets the prefix field
+func WithPrefix(prefix string) LoggerOption {
+	return loggerPrefixOption{prefix: prefix}
+}
+
+// NewLoggerWithOptions creates a new Logger with functional options
+//
+// Logger represents a leveled logger
+// This example demonstrates:
+// 1. Interface-based options with an unexported apply method (grpc-go style)
+// 2. Options that can be compared and printed
+// 3. Custom options implemented through the LoggerOptionFunc adapter
+func NewLoggerWithOptions(opts ...LoggerOption) *Logger {
+	v := &Logger{
+		level: "info",
+	}
+	for _, opt := range opts {
+		opt.apply(v)
+	}
+	return v
+}
+
+// GetLevel returns the level field
+func (l *Logger) GetLevel() string {
+	return l.level
+}
+
+// GetOutput returns the output field
+func (l *Logger) GetOutput() io.Writer {
+	return l.output
+}
+
+// GetPrefix returns the prefix field
+func (l *Logger) GetPrefix() string {
+	return l.prefix
+}
@@ -0,0 +1,61 @@
+package options
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestLoggerInterfaceOptions(t *testing.T) {
+	logger := NewLoggerWithOptions(
+		WithLevel("debug"),
+		WithOutput(os.Stderr),
+		WithPrefix("app: "),
+	)
+
+	if logger.GetLevel() != "debug" {
+		t.Errorf("Expected level debug, got %s", logger.GetLevel())
+	}
+
+	if logger.GetOutput() != os.Stderr {
+		t.Error("Expected output os.Stderr")
+	}
+
+	if logger.GetPrefix() != "app: " {
+		t.Errorf("Expected prefix app: , got %s", logger.GetPrefix())
+	}
+
+	// Defaults still apply
+	if NewLoggerWithOptions().GetLevel() != "info" {
+		t.Error("Expected default level info")
+	}
+}
+
+func TestLoggerOptionValues(t *testing.T) {
+	// Options are values that can be compared and printed
+	if WithLevel("debug") != WithLevel("debug") {
+		t.Error("Equal options should compare equal")
+	}
+
+	if WithLevel("debug") == WithLevel("warn") {
+		t.Error("Different options should not compare equal")
+	}
+
+	if s := fmt.Sprint(WithLevel("debug")); s != "WithLevel(debug)" {
+		t.Errorf("Expected WithLevel(debug), got %s", s)
+	}
+}
+
+func TestLoggerOptionFunc(t *testing.T) {
+	// Custom options are written with the function adapter
+	quiet := LoggerOptionFunc(func(l *Logger) {
+		l.level = "error"
+		l.prefix = ""
+	})
+
+	logger := NewLoggerWithOptions(WithPrefix("app: "), quiet)
+
+	if logger.GetLevel() != "error" || logger.GetPrefix() != "" {
+		t.Errorf("Expected custom option to apply, got level %s prefix %q", logger.GetLevel(), logger.GetPrefix())
+	}
+}
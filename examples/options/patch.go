@@ -0,0 +1,32 @@
+package options
+
+import "time"
+
+//go:generate go run ../../. -type=ProfilePatch -constructorTypes=builder,options -optionNaming=prefixed -withGetter
+
+// ProfilePatch represents a partial update of a user profile
+// This example demonstrates:
+// 1. Presence tracking with a field tagged constructor:"presence"
+// 2. HasX and IsSet telling fields set to their zero value from unset ones
+// 3. Defaults that do not count as set
+type ProfilePatch struct {
+	nickname string
+	age      int
+	timeout  time.Duration `constructor:"default=5s"`
+	set      uint8         `constructor:"presence"`
+}
+
+// Apply returns the fields of the patch that were set, keyed by field name
+func (p *ProfilePatch) Apply() map[string]any {
+	changes := map[string]any{}
+	if p.HasNickname() {
+		changes["nickname"] = p.nickname
+	}
+	if p.HasAge() {
+		changes["age"] = p.age
+	}
+	if p.HasTimeout() {
+		changes["timeout"] = p.timeout
+	}
+	return changes
+}
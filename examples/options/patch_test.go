@@ -0,0 +1,42 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProfilePatchOptions(t *testing.T) {
+	patch := NewProfilePatchWithOptions(WithProfilePatchAge(0))
+
+	// An explicit zero value is set, an untouched field is not
+	if !patch.HasAge() {
+		t.Error("HasAge() = false, want true")
+	}
+	if patch.HasNickname() {
+		t.Error("HasNickname() = true, want false")
+	}
+
+	// Defaults apply without counting as set
+	if patch.GetTimeout() != 5*time.Second {
+		t.Errorf("GetTimeout() = %v, want 5s", patch.GetTimeout())
+	}
+	if patch.IsSet("timeout") {
+		t.Error("IsSet(\"timeout\") = true, want false")
+	}
+
+	if !reflect.DeepEqual(patch.Apply(), map[string]any{"age": 0}) {
+		t.Errorf("Apply() = %v, want only age", patch.Apply())
+	}
+}
+
+func TestProfilePatchBuilder(t *testing.T) {
+	patch := NewProfilePatchBuilder().Nickname("").Timeout(time.Second).Build()
+
+	if !patch.IsSet("nickname") || !patch.IsSet("timeout") {
+		t.Error("nickname and timeout should be set")
+	}
+	if patch.IsSet("age") || patch.IsSet("unknown") {
+		t.Error("age and unknown fields should not be set")
+	}
+}
@@ -0,0 +1,159 @@
+package options
+
+import "time"
+
+// Code generated by constructor. DO NOT EDIT.
+
+// ProfilePatchBuilder is a builder for ProfilePatch
+//
+// ProfilePatch represents a partial update of a user profile
+// This example demonstrates:
+// 1. Presence tracking with a field tagged constructor:"presence"
+// 2. HasX and IsSet telling fields set to their zero value from unset ones
+// 3. Defaults that do not count as set
+type ProfilePatchBuilder struct {
+	nickname string
+	age      int
+	timeout  time.Duration
+	set      uint8
+}
+
+// NewProfilePatchBuilder creates a new ProfilePatchBuilder
+func NewProfilePatchBuilder() *ProfilePatchBuilder {
+	return &ProfilePatchBuilder{
+		timeout: 5 * time.Second,
+	}
+}
+
+// Nickname sets the nickname field
+func (b *ProfilePatchBuilder) Nickname(nickname string) *ProfilePatchBuilder {
+	b.nickname = nickname
+	b.set |= profilePatchNicknamePresent
+	return b
+}
+
+// Age sets the age field
+func (b *ProfilePatchBuilder) Age(age int) *ProfilePatchBuilder {
+	b.age = age
+	b.set |= profilePatchAgePresent
+	return b
+}
+
+// Timeout sets the timeout field
+func (b *ProfilePatchBuilder) Timeout(timeout time.Duration) *ProfilePatchBuilder {
+	b.timeout = timeout
+	b.set |= profilePatchTimeoutPresent
+	return b
+}
+
+// Build builds the ProfilePatch
+func (b *ProfilePatchBuilder) Build() *ProfilePatch {
+	v := &ProfilePatch{
+		nickname: b.nickname,
+		age:      b.age,
+		timeout:  b.timeout,
+		set:      b.set,
+	}
+	return v
+}
+
+// ProfilePatchOption is a functional option for configuring ProfilePatch
+//
+// ProfilePatch represents a partial update of a user profile
+// This example demonstrates:
+// 1. Presence tracking with a field tagged constructor:"presence"
+// 2. HasX and IsSet telling fields set to their zero value from unset ones
+// 3. Defaults that do not count as set
+type ProfilePatchOption func(*ProfilePatch)
+
+// WithProfilePatchNickname sets the nickname field
+func WithProfilePatchNickname(nickname string) ProfilePatchOption {
+	return func(s *ProfilePatch) {
+		s.nickname = nickname
+		s.set |= profilePatchNicknamePresent
+	}
+}
+
+// WithProfilePatchAge sets the age field
+func WithProfilePatchAge(age int) ProfilePatchOption {
+	return func(s *ProfilePatch) {
+		s.age = age
+		s.set |= profilePatchAgePresent
+	}
+}
+
+// WithProfilePatchTimeout sets the timeout field
+func WithProfilePatchTimeout(timeout time.Duration) ProfilePatchOption {
+	return func(s *ProfilePatch) {
+		s.timeout = timeout
+		s.set |= profilePatchTimeoutPresent
+	}
+}
+
+// NewProfilePatchWithOptions creates a new ProfilePatch with functional options
+//
+// ProfilePatch represents a partial update of a user profile
+// This example demonstrates:
+// 1. Presence tracking with a field tagged constructor:"presence"
+// 2. HasX and IsSet telling fields set to their zero value from unset ones
+// 3. Defaults that do not count as set
+func NewProfilePatchWithOptions(opts ...ProfilePatchOption) *ProfilePatch {
+	v := &ProfilePatch{
+		timeout: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Presence bits of the ProfilePatch fields, recorded in set when a field is set explicitly
+const (
+	profilePatchNicknamePresent uint8 = 1 << iota
+	profilePatchAgePresent
+	profilePatchTimeoutPresent
+)
+
+// HasNickname reports whether the nickname field was set explicitly, even to its zero value
+func (p *ProfilePatch) HasNickname() bool {
+	return p.set&profilePatchNicknamePresent != 0
+}
+
+// HasAge reports whether the age field was set explicitly, even to its zero value
+func (p *ProfilePatch) HasAge() bool {
+	return p.set&profilePatchAgePresent != 0
+}
+
+// HasTimeout reports whether the timeout field was set explicitly, even to its zero value
+func (p *ProfilePatch) HasTimeout() bool {
+	return p.set&profilePatchTimeoutPresent != 0
+}
+
+// IsSet reports whether the field with the given name was set explicitly.
+// Unknown names are reported as not set.
+func (p *ProfilePatch) IsSet(field string) bool {
+	switch field {
+	case "nickname":
+		return p.HasNickname()
+	case "age":
+		return p.HasAge()
+	case "timeout":
+		return p.HasTimeout()
+	}
+	return false
+}
+
+// GetNickname returns the nickname field
+func (p *ProfilePatch) GetNickname() string {
+	return p.nickname
+}
+
+// GetAge returns the age field
+func (p *ProfilePatch) GetAge() int {
+	return p.age
+}
+
+// GetTimeout returns the timeout field
+func (p *ProfilePatch) GetTimeout() time.Duration {
+	return p.timeout
+}
@@ -7,9 +7,14 @@ package options
 // 1. Functional options pattern with getters
 // 2. Option-only fields (no getter) with constructor:"getter:false"
 // 3. Getter-only fields (no option) with constructor:"setter:false"
+// 4. Field comments carried into the docs of the options and getters
 type Server struct {
-	address    string // Will have With option and getter
-	port       int    // Will have With option and getter
-	tlsKey     string `constructor:"getter:false"` // Will have With option but NO getter (security)
-	instanceID string `constructor:"setter:false"` // Will have getter but NO With option (auto-generated)
+	// Address to listen on, e.g., "0.0.0.0"
+	address string
+	// TCP port to listen on
+	port int
+	// Private key of the TLS certificate, with no getter
+	tlsKey string `constructor:"getter:false"`
+	// Identifier assigned when the server starts, with no option
+	instanceID string `constructor:"setter:false"`
 }
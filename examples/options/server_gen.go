@@ -3,9 +3,18 @@ package options
 // Code generated by constructor. DO NOT EDIT.
 
 // ServerOption is a functional option for configuring Server
+//
+// Server represents a server configuration
+// This example demonstrates:
+// 1. Functional options pattern with getters
+// 2. Option-only fields (no getter) with constructor:"getter:false"
+// 3. Getter-only fields (no option) with constructor:"setter:false"
+// 4. Field comments carried into the docs of the options and getters
 type ServerOption func(*Server)
 
 // WithAddress sets the address field
+//
+// Address to listen on, e.g., "0.0.0.0"
 func WithAddress(address string) ServerOption {
 	return func(s *Server) {
 		s.address = address
@@ -13,6 +22,8 @@ func WithAddress(address string) ServerOption {
 }
 
 // WithPort sets the port field
+//
+// TCP port to listen on
 func WithPort(port int) ServerOption {
 	return func(s *Server) {
 		s.port = port
@@ -20,6 +31,8 @@ func WithPort(port int) ServerOption {
 }
 
 // WithTlsKey sets the tlsKey field
+//
+// Private key of the TLS certificate, with no getter
 func WithTlsKey(tlsKey string) ServerOption {
 	return func(s *Server) {
 		s.tlsKey = tlsKey
@@ -27,6 +40,13 @@ func WithTlsKey(tlsKey string) ServerOption {
 }
 
 // NewServerWithOptions creates a new Server with functional options
+//
+// Server represents a server configuration
+// This example demonstrates:
+// 1. Functional options pattern with getters
+// 2. Option-only fields (no getter) with constructor:"getter:false"
+// 3. Getter-only fields (no option) with constructor:"setter:false"
+// 4. Field comments carried into the docs of the options and getters
 func NewServerWithOptions(opts ...ServerOption) *Server {
 	v := &Server{}
 	for _, opt := range opts {
@@ -36,16 +56,22 @@ func NewServerWithOptions(opts ...ServerOption) *Server {
 }
 
 // GetAddress returns the address field
+//
+// Address to listen on, e.g., "0.0.0.0"
 func (s *Server) GetAddress() string {
 	return s.address
 }
 
 // GetPort returns the port field
+//
+// TCP port to listen on
 func (s *Server) GetPort() int {
 	return s.port
 }
 
 // GetInstanceID returns the instanceID field
+//
+// Identifier assigned when the server starts, with no option
 func (s *Server) GetInstanceID() string {
 	return s.instanceID
 }
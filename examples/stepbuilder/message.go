@@ -0,0 +1,18 @@
+package stepbuilder
+
+import "time"
+
+//go:generate go run ../../. -type=Message -constructorTypes=stepBuilder -withGetter
+
+// Message represents an outgoing email
+// This example demonstrates:
+// 1. A step builder enforcing required fields at compile time
+// 2. Required fields set in declaration order before the optional ones
+// 3. Default values of optional fields
+type Message struct {
+	from    string   `constructor:"required"`
+	to      []string `constructor:"required"`
+	subject string   `constructor:"required"`
+	body    string
+	ttl     time.Duration `constructor:"default=24h"`
+}
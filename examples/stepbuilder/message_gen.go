@@ -0,0 +1,122 @@
+package stepbuilder
+
+import "time"
+
+// Code generated by constructor. DO NOT EDIT.
+
+// MessageFromStep is the step of the Message builder setting the required from field
+type MessageFromStep interface {
+	// From sets the from field
+	From(from string) MessageToStep
+}
+
+// MessageToStep is the step of the Message builder setting the required to field
+type MessageToStep interface {
+	// To sets the to field
+	To(to []string) MessageSubjectStep
+}
+
+// MessageSubjectStep is the step of the Message builder setting the required subject field
+type MessageSubjectStep interface {
+	// Subject sets the subject field
+	Subject(subject string) MessageBuildStep
+}
+
+// MessageBuildStep is the last step of the Message builder, setting the optional fields
+type MessageBuildStep interface {
+	// Body sets the body field
+	Body(body string) MessageBuildStep
+	// Ttl sets the ttl field
+	Ttl(ttl time.Duration) MessageBuildStep
+	// Build builds the Message
+	Build() *Message
+}
+
+// messageStepBuilder implements the steps of the Message builder
+type messageStepBuilder struct {
+	from    string
+	to      []string
+	subject string
+	body    string
+	ttl     time.Duration
+}
+
+// NewMessageBuilder creates a new step builder for Message, starting with the first required field
+//
+// Message represents an outgoing email
+// This example demonstrates:
+// 1. A step builder enforcing required fields at compile time
+// 2. Required fields set in declaration order before the optional ones
+// 3. Default values of optional fields
+func NewMessageBuilder() MessageFromStep {
+	return &messageStepBuilder{
+		ttl: 24 * time.Hour,
+	}
+}
+
+// From sets the from field
+func (b *messageStepBuilder) From(from string) MessageToStep {
+	b.from = from
+	return b
+}
+
+// To sets the to field
+func (b *messageStepBuilder) To(to []string) MessageSubjectStep {
+	b.to = to
+	return b
+}
+
+// Subject sets the subject field
+func (b *messageStepBuilder) Subject(subject string) MessageBuildStep {
+	b.subject = subject
+	return b
+}
+
+// Body sets the body field
+func (b *messageStepBuilder) Body(body string) MessageBuildStep {
+	b.body = body
+	return b
+}
+
+// Ttl sets the ttl field
+func (b *messageStepBuilder) Ttl(ttl time.Duration) MessageBuildStep {
+	b.ttl = ttl
+	return b
+}
+
+// Build builds the Message
+func (b *messageStepBuilder) Build() *Message {
+	v := &Message{
+		from:    b.from,
+		to:      b.to,
+		subject: b.subject,
+		body:    b.body,
+		ttl:     b.ttl,
+	}
+	return v
+}
+
+// GetFrom returns the from field
+func (m *Message) GetFrom() string {
+	return m.from
+}
+
+// GetTo returns the to field
+func (m *Message) GetTo() []string {
+	return m.to
+}
+
+// GetSubject returns the subject field
+func (m *Message) GetSubject() string {
+	return m.subject
+}
+
+// GetBody returns the body field
+func (m *Message) GetBody() string {
+	return m.body
+}
+
+// GetTtl returns the ttl field
+func (m *Message) GetTtl() time.Duration {
+	return m.ttl
+}
@@ -0,0 +1,55 @@
+package stepbuilder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMessageStepBuilder(t *testing.T) {
+	msg := NewMessageBuilder().
+		From("alice@example.com").
+		To([]string{"bob@example.com"}).
+		Subject("Hello").
+		Body("Hi Bob").
+		Build()
+
+	if msg.GetFrom() != "alice@example.com" {
+		t.Errorf("Expected from alice@example.com, got %s", msg.GetFrom())
+	}
+
+	if !reflect.DeepEqual(msg.GetTo(), []string{"bob@example.com"}) {
+		t.Errorf("Expected to [bob@example.com], got %v", msg.GetTo())
+	}
+
+	if msg.GetSubject() != "Hello" {
+		t.Errorf("Expected subject Hello, got %s", msg.GetSubject())
+	}
+
+	if msg.GetBody() != "Hi Bob" {
+		t.Errorf("Expected body Hi Bob, got %s", msg.GetBody())
+	}
+
+	if msg.GetTtl() != 24*time.Hour {
+		t.Errorf("Expected default ttl 24h, got %v", msg.GetTtl())
+	}
+}
+
+func TestMessageStepOrder(t *testing.T) {
+	// Each step only offers the setter of the next required field
+	stepType := reflect.TypeOf((*MessageFromStep)(nil)).Elem()
+	if stepType.NumMethod() != 1 {
+		t.Errorf("MessageFromStep should have a single method, got %d", stepType.NumMethod())
+	}
+
+	// Build is only reachable once every required field is set
+	_, hasBuild := reflect.TypeOf((*MessageSubjectStep)(nil)).Elem().MethodByName("Build")
+	if hasBuild {
+		t.Error("Build() should not be available before the last required field is set")
+	}
+
+	_, hasBuild = reflect.TypeOf((*MessageBuildStep)(nil)).Elem().MethodByName("Build")
+	if !hasBuild {
+		t.Error("Build() should be available on the build step")
+	}
+}
@@ -0,0 +1,14 @@
+package validation
+
+//go:generate go run ../../. -type=Account -constructorTypes=options -withGetter -withSetter -setterChecks
+
+// Account represents a mutable user account
+// This example demonstrates:
+// 1. Setters generated alongside getters for private fields
+// 2. Setters checking required fields and field rules, returning an error
+// 3. The same checks in the options constructor
+type Account struct {
+	owner string `constructor:"required"`
+	email string `constructor:"match=^[^@]+@[^@]+$"`
+	quota int    `constructor:"min=0"`
+}
@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Code generated by constructor. DO NOT EDIT.
+
+// AccountValidationError reports a field of Account violating a rule of its constructor tag
+type AccountValidationError struct {
+	Field string // Field name
+	Rule  string // Violated rule, as written in the tag
+}
+
+// Error implements the error interface
+func (e *AccountValidationError) Error() string {
+	return fmt.Sprintf("invalid Account: field %s violates rule %s", e.Field, e.Rule)
+}
+
+var accountEmailPattern = regexp.MustCompile(`^[^@]+@[^@]+$`)
+
+// validateAccountFields checks the fields of v against the rules of their constructor tags
+func validateAccountFields(v *Account) error {
+	if !accountEmailPattern.MatchString(v.email) {
+		return &AccountValidationError{Field: "email", Rule: "match=^[^@]+@[^@]+$"}
+	}
+	if v.quota < 0 {
+		return &AccountValidationError{Field: "quota", Rule: "min=0"}
+	}
+	return nil
+}
+
+// AccountOption is a functional option for configuring Account
+//
+// Account represents a mutable user account
+// This example demonstrates:
+// 1. Setters generated alongside getters for private fields
+// 2. Setters checking required fields and field rules, returning an error
+// 3. The same checks in the options constructor
+type AccountOption func(*Account)
+
+// WithOwner sets the owner field
+func WithOwner(owner string) AccountOption {
+	return func(s *Account) {
+		s.owner = owner
+	}
+}
+
+// WithEmail sets the email field
+func WithEmail(email string) AccountOption {
+	return func(s *Account) {
+		s.email = email
+	}
+}
+
+// WithQuota sets the quota field
+func WithQuota(quota int) AccountOption {
+	return func(s *Account) {
+		s.quota = quota
+	}
+}
+
+// NewAccountWithOptions creates a new Account with functional options,
+// returning an error if a required field was left at its zero value or a field violates the rules of its tag
+//
+// Account represents a mutable user account
+// This example demonstrates:
+// 1. Setters generated alongside getters for private fields
+// 2. Setters checking required fields and field rules, returning an error
+// 3. The same checks in the options constructor
+func NewAccountWithOptions(opts ...AccountOption) (*Account, error) {
+	v := &Account{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	var missing []string
+	if v.owner == "" {
+		missing = append(missing, "owner")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required fields of Account: %s", strings.Join(missing, ", "))
+	}
+	if err := validateAccountFields(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetOwner returns the owner field
+func (a *Account) GetOwner() string {
+	return a.owner
+}
+
+// GetEmail returns the email field
+func (a *Account) GetEmail() string {
+	return a.email
+}
+
+// GetQuota returns the quota field
+func (a *Account) GetQuota() int {
+	return a.quota
+}
+
+// SetOwner sets the owner field, returning an error if the value is invalid
+func (a *Account) SetOwner(owner string) error {
+	if owner == "" {
+		return errors.New("missing required fields of Account: owner")
+	}
+	a.owner = owner
+	return nil
+}
+
+// SetEmail sets the email field, returning an error if the value is invalid
+func (a *Account) SetEmail(email string) error {
+	if !accountEmailPattern.MatchString(email) {
+		return &AccountValidationError{Field: "email", Rule: "match=^[^@]+@[^@]+$"}
+	}
+	a.email = email
+	return nil
+}
+
+// SetQuota sets the quota field, returning an error if the value is invalid
+func (a *Account) SetQuota(quota int) error {
+	if quota < 0 {
+		return &AccountValidationError{Field: "quota", Rule: "min=0"}
+	}
+	a.quota = quota
+	return nil
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAccountSetters(t *testing.T) {
+	account, err := NewAccountWithOptions(WithOwner("alice"), WithEmail("alice@example.com"))
+	if err != nil {
+		t.Fatalf("NewAccountWithOptions failed: %v", err)
+	}
+
+	if err := account.SetEmail("alice@example.org"); err != nil {
+		t.Errorf("SetEmail failed: %v", err)
+	}
+	if account.GetEmail() != "alice@example.org" {
+		t.Errorf("Expected email alice@example.org, got %s", account.GetEmail())
+	}
+
+	if err := account.SetQuota(10); err != nil {
+		t.Errorf("SetQuota failed: %v", err)
+	}
+	if account.GetQuota() != 10 {
+		t.Errorf("Expected quota 10, got %d", account.GetQuota())
+	}
+}
+
+func TestAccountSetterChecks(t *testing.T) {
+	account, err := NewAccountWithOptions(WithOwner("alice"), WithEmail("alice@example.com"))
+	if err != nil {
+		t.Fatalf("NewAccountWithOptions failed: %v", err)
+	}
+
+	if err := account.SetOwner(""); err == nil || err.Error() != "missing required fields of Account: owner" {
+		t.Errorf("SetOwner with empty owner: expected missing field error, got %v", err)
+	}
+
+	var ruleErr *AccountValidationError
+	if err := account.SetQuota(-1); !errors.As(err, &ruleErr) || ruleErr.Rule != "min=0" {
+		t.Errorf("SetQuota(-1): expected min=0 violation, got %v", err)
+	}
+
+	if err := account.SetEmail("invalid"); !errors.As(err, &ruleErr) || ruleErr.Field != "email" {
+		t.Errorf("SetEmail(invalid): expected email violation, got %v", err)
+	}
+
+	// Rejected values leave the fields unchanged
+	if account.GetOwner() != "alice" || account.GetQuota() != 0 || account.GetEmail() != "alice@example.com" {
+		t.Errorf("Rejected values should not be set, got %s %d %s", account.GetOwner(), account.GetQuota(), account.GetEmail())
+	}
+}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+//go:generate go run ../../. -type=Endpoint -constructorTypes=allArgs,builder -init=resolve
+
+// Endpoint represents a remote service endpoint
+// This example demonstrates:
+// 1. Field rules declared in constructor tags, reported as *EndpointValidationError
+// 2. An init method taking a context.Context and returning an error
+// 3. Automatic detection of the Validate() error method
+// 4. Constructors returning (*Endpoint, error)
+type Endpoint struct {
+	scheme string   `constructor:"oneof=http|https"`
+	host   string   `constructor:"nonempty,match=^[a-z0-9.-]+$"`
+	port   int      `constructor:"min=1,max=65535"`
+	url    *url.URL `constructor:"-"` // Derived by resolve
+}
+
+// resolve derives the URL of the endpoint
+func (e *Endpoint) resolve(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	e.url = &url.URL{Scheme: e.scheme, Host: net.JoinHostPort(e.host, strconv.Itoa(e.port))}
+	return nil
+}
+
+// Validate checks the invariants spanning several fields, after the field rules
+func (e *Endpoint) Validate() error {
+	if e.scheme == "https" && e.port == 80 {
+		return errors.New("https endpoint must not use port 80")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Code generated by constructor. DO NOT EDIT.
+
+// EndpointValidationError reports a field of Endpoint violating a rule of its constructor tag
+type EndpointValidationError struct {
+	Field string // Field name
+	Rule  string // Violated rule, as written in the tag
+}
+
+// Error implements the error interface
+func (e *EndpointValidationError) Error() string {
+	return fmt.Sprintf("invalid Endpoint: field %s violates rule %s", e.Field, e.Rule)
+}
+
+var endpointHostPattern = regexp.MustCompile(`^[a-z0-9.-]+$`)
+
+// validateEndpointFields checks the fields of v against the rules of their constructor tags
+func validateEndpointFields(v *Endpoint) error {
+	if v.scheme != "http" && v.scheme != "https" {
+		return &EndpointValidationError{Field: "scheme", Rule: "oneof=http|https"}
+	}
+	if len(v.host) == 0 {
+		return &EndpointValidationError{Field: "host", Rule: "nonempty"}
+	}
+	if !endpointHostPattern.MatchString(v.host) {
+		return &EndpointValidationError{Field: "host", Rule: "match=^[a-z0-9.-]+$"}
+	}
+	if v.port < 1 {
+		return &EndpointValidationError{Field: "port", Rule: "min=1"}
+	}
+	if v.port > 65535 {
+		return &EndpointValidationError{Field: "port", Rule: "max=65535"}
+	}
+	return nil
+}
+
+// NewEndpoint creates a new Endpoint, returning an error if a field violates the rules of its tag or resolve or Validate fails
+//
+// Endpoint represents a remote service endpoint
+// This example demonstrates:
+// 1. Field rules declared in constructor tags, reported as *EndpointValidationError
+// 2. An init method taking a context.Context and returning an error
+// 3. Automatic detection of the Validate() error method
+// 4. Constructors returning (*Endpoint, error)
+func NewEndpoint(ctx context.Context, scheme string, host string, port int) (*Endpoint, error) {
+	v := &Endpoint{
+		scheme: scheme,
+		host:   host,
+		port:   port,
+	}
+	if err := validateEndpointFields(v); err != nil {
+		return nil, err
+	}
+	if err := v.resolve(ctx); err != nil {
+		return nil, err
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// EndpointBuilder is a builder for Endpoint
+//
+// Endpoint represents a remote service endpoint
+// This example demonstrates:
+// 1. Field rules declared in constructor tags, reported as *EndpointValidationError
+// 2. An init method taking a context.Context and returning an error
+// 3. Automatic detection of the Validate() error method
+// 4. Constructors returning (*Endpoint, error)
+type EndpointBuilder struct {
+	scheme string
+	host   string
+	port   int
+}
+
+// NewEndpointBuilder creates a new EndpointBuilder
+func NewEndpointBuilder() *EndpointBuilder {
+	return &EndpointBuilder{}
+}
+
+// Scheme sets the scheme field
+func (b *EndpointBuilder) Scheme(scheme string) *EndpointBuilder {
+	b.scheme = scheme
+	return b
+}
+
+// Host sets the host field
+func (b *EndpointBuilder) Host(host string) *EndpointBuilder {
+	b.host = host
+	return b
+}
+
+// Port sets the port field
+func (b *EndpointBuilder) Port(port int) *EndpointBuilder {
+	b.port = port
+	return b
+}
+
+// Build builds the Endpoint, returning an error if a field violates the rules of its tag or resolve or Validate fails
+func (b *EndpointBuilder) Build(ctx context.Context) (*Endpoint, error) {
+	v := &Endpoint{
+		scheme: b.scheme,
+		host:   b.host,
+		port:   b.port,
+	}
+	if err := validateEndpointFields(v); err != nil {
+		return nil, err
+	}
+	if err := v.resolve(ctx); err != nil {
+		return nil, err
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
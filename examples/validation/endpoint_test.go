@@ -0,0 +1,93 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewEndpoint(t *testing.T) {
+	endpoint, err := NewEndpoint(context.Background(), "https", "api.example.com", 443)
+	if err != nil {
+		t.Fatalf("NewEndpoint failed: %v", err)
+	}
+
+	// Verify that resolve() was called
+	if endpoint.url.String() != "https://api.example.com:443" {
+		t.Errorf("Expected url https://api.example.com:443, got %s", endpoint.url)
+	}
+}
+
+func TestEndpointBuilder(t *testing.T) {
+	endpoint, err := NewEndpointBuilder().
+		Scheme("http").
+		Host("localhost").
+		Port(8080).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if endpoint.url.String() != "http://localhost:8080" {
+		t.Errorf("Expected url http://localhost:8080, got %s", endpoint.url)
+	}
+}
+
+func TestEndpointFieldRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme string
+		host   string
+		port   int
+		field  string
+		rule   string
+	}{
+		{"unknown scheme", "ftp", "localhost", 21, "scheme", "oneof=http|https"},
+		{"empty host", "https", "", 443, "host", "nonempty"},
+		{"invalid host", "https", "Local_Host", 443, "host", "match=^[a-z0-9.-]+$"},
+		{"port too low", "https", "localhost", 0, "port", "min=1"},
+		{"port too high", "https", "localhost", 70000, "port", "max=65535"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEndpoint(context.Background(), tt.scheme, tt.host, tt.port)
+
+			var ruleErr *EndpointValidationError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("Expected *EndpointValidationError, got %v", err)
+			}
+			if ruleErr.Field != tt.field || ruleErr.Rule != tt.rule {
+				t.Errorf("Expected %s to violate %s, got %s violating %s", tt.field, tt.rule, ruleErr.Field, ruleErr.Rule)
+			}
+		})
+	}
+
+	// The builder checks the same rules
+	_, err := NewEndpointBuilder().Scheme("http").Host("localhost").Port(70000).Build(context.Background())
+	if err == nil || err.Error() != "invalid Endpoint: field port violates rule max=65535" {
+		t.Errorf("Expected port rule violation, got %v", err)
+	}
+}
+
+func TestEndpointValidation(t *testing.T) {
+	// Validate() is called by every constructor
+	if _, err := NewEndpoint(context.Background(), "https", "localhost", 80); err == nil {
+		t.Error("NewEndpoint should fail for https on port 80")
+	}
+
+	if _, err := NewEndpointBuilder().Scheme("https").Host("localhost").Port(80).Build(context.Background()); err == nil {
+		t.Error("Build should fail for https on port 80")
+	}
+}
+
+func TestEndpointInitError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Errors of resolve() are returned as is
+	_, err := NewEndpoint(ctx, "https", "api.example.com", 443)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package validation
+
+//go:generate go run ../../. -type=Listener -constructorTypes=options -optionStyle=error
+
+// Listener represents a network listener configuration
+// This example demonstrates:
+// 1. Options returning an error (-optionStyle=error)
+// 2. Field rules checked as soon as an option is applied
+// 3. All invalid options reported together through errors.Join
+type Listener struct {
+	network string `constructor:"default=tcp,oneof=tcp|udp"`
+	port    int    `constructor:"min=1,max=65535"`
+	backlog int    `constructor:"min=0"`
+}
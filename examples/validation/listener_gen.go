@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code generated by constructor. DO NOT EDIT.
+
+// ListenerValidationError reports a field of Listener violating a rule of its constructor tag
+type ListenerValidationError struct {
+	Field string // Field name
+	Rule  string // Violated rule, as written in the tag
+}
+
+// Error implements the error interface
+func (e *ListenerValidationError) Error() string {
+	return fmt.Sprintf("invalid Listener: field %s violates rule %s", e.Field, e.Rule)
+}
+
+// validateListenerFields checks the fields of v against the rules of their constructor tags
+func validateListenerFields(v *Listener) error {
+	if v.network != "tcp" && v.network != "udp" {
+		return &ListenerValidationError{Field: "network", Rule: "oneof=tcp|udp"}
+	}
+	if v.port < 1 {
+		return &ListenerValidationError{Field: "port", Rule: "min=1"}
+	}
+	if v.port > 65535 {
+		return &ListenerValidationError{Field: "port", Rule: "max=65535"}
+	}
+	if v.backlog < 0 {
+		return &ListenerValidationError{Field: "backlog", Rule: "min=0"}
+	}
+	return nil
+}
+
+// ListenerOption is a functional option for configuring Listener, returning an error if its value is invalid
+//
+// Listener represents a network listener configuration
+// This example demonstrates:
+// 1. Options returning an error (-optionStyle=error)
+// 2. Field rules checked as soon as an option is applied
+// 3. All invalid options reported together through errors.Join
+type ListenerOption func(*Listener) error
+
+// WithNetwork sets the network field, failing if the value violates the rules of its tag
+func WithNetwork(network string) ListenerOption {
+	return func(s *Listener) error {
+		if network != "tcp" && network != "udp" {
+			return &ListenerValidationError{Field: "network", Rule: "oneof=tcp|udp"}
+		}
+		s.network = network
+		return nil
+	}
+}
+
+// WithPort sets the port field, failing if the value violates the rules of its tag
+func WithPort(port int) ListenerOption {
+	return func(s *Listener) error {
+		if port < 1 {
+			return &ListenerValidationError{Field: "port", Rule: "min=1"}
+		}
+		if port > 65535 {
+			return &ListenerValidationError{Field: "port", Rule: "max=65535"}
+		}
+		s.port = port
+		return nil
+	}
+}
+
+// WithBacklog sets the backlog field, failing if the value violates the rules of its tag
+func WithBacklog(backlog int) ListenerOption {
+	return func(s *Listener) error {
+		if backlog < 0 {
+			return &ListenerValidationError{Field: "backlog", Rule: "min=0"}
+		}
+		s.backlog = backlog
+		return nil
+	}
+}
+
+// NewListenerWithOptions creates a new Listener with functional options,
+// returning an error if an option fails or a field violates the rules of its tag
+//
+// Listener represents a network listener configuration
+// This example demonstrates:
+// 1. Options returning an error (-optionStyle=error)
+// 2. Field rules checked as soon as an option is applied
+// 3. All invalid options reported together through errors.Join
+func NewListenerWithOptions(opts ...ListenerOption) (*Listener, error) {
+	v := &Listener{
+		network: "tcp",
+	}
+	var errs []error
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if err := validateListenerFields(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestListenerErrorOptions(t *testing.T) {
+	listener, err := NewListenerWithOptions(WithPort(8080), WithBacklog(128))
+	if err != nil {
+		t.Fatalf("NewListenerWithOptions failed: %v", err)
+	}
+
+	if listener.network != "tcp" {
+		t.Errorf("Expected default network tcp, got %s", listener.network)
+	}
+
+	if listener.port != 8080 {
+		t.Errorf("Expected port 8080, got %d", listener.port)
+	}
+
+	if listener.backlog != 128 {
+		t.Errorf("Expected backlog 128, got %d", listener.backlog)
+	}
+}
+
+func TestListenerInvalidOptions(t *testing.T) {
+	_, err := NewListenerWithOptions(WithNetwork("unix"), WithPort(8080), WithBacklog(-1))
+	if err == nil {
+		t.Fatal("Expected error for invalid options")
+	}
+
+	// Every invalid option is reported
+	var rules []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var ruleErr *ListenerValidationError
+		if errors.As(e, &ruleErr) {
+			rules = append(rules, ruleErr.Field+" "+ruleErr.Rule)
+		}
+	}
+	if len(rules) != 2 || rules[0] != "network oneof=tcp|udp" || rules[1] != "backlog min=0" {
+		t.Errorf("Expected network and backlog violations, got %v", rules)
+	}
+}
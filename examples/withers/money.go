@@ -0,0 +1,14 @@
+package withers
+
+//go:generate go run ../../. -type=Money -constructorTypes=allArgs,withers -returnValue -withersDeepCopy -withGetter
+
+// Money represents an immutable amount of money
+// This example demonstrates:
+// 1. Wither methods returning modified copies of a value type
+// 2. Cloning of map fields so copies do not share them (-withersDeepCopy)
+// 3. Getters exposing the private fields read-only
+type Money struct {
+	amount   int64
+	currency string
+	labels   map[string]string
+}
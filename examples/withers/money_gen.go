@@ -0,0 +1,55 @@
+package withers
+
+import "maps"
+
+// Code generated by constructor. DO NOT EDIT.
+
+// NewMoney creates a new Money
+//
+// Money represents an immutable amount of money
+// This example demonstrates:
+// 1. Wither methods returning modified copies of a value type
+// 2. Cloning of map fields so copies do not share them (-withersDeepCopy)
+// 3. Getters exposing the private fields read-only
+func NewMoney(amount int64, currency string, labels map[string]string) Money {
+	return Money{
+		amount:   amount,
+		currency: currency,
+		labels:   labels,
+	}
+}
+
+// WithAmount returns a copy of m with the amount field set
+func (m Money) WithAmount(amount int64) Money {
+	m.labels = maps.Clone(m.labels)
+	m.amount = amount
+	return m
+}
+
+// WithCurrency returns a copy of m with the currency field set
+func (m Money) WithCurrency(currency string) Money {
+	m.labels = maps.Clone(m.labels)
+	m.currency = currency
+	return m
+}
+
+// WithLabels returns a copy of m with the labels field set
+func (m Money) WithLabels(labels map[string]string) Money {
+	m.labels = labels
+	return m
+}
+
+// GetAmount returns the amount field
+func (m *Money) GetAmount() int64 {
+	return m.amount
+}
+
+// GetCurrency returns the currency field
+func (m *Money) GetCurrency() string {
+	return m.currency
+}
+
+// GetLabels returns the labels field
+func (m *Money) GetLabels() map[string]string {
+	return m.labels
+}
@@ -0,0 +1,32 @@
+package withers
+
+import "testing"
+
+func TestMoneyWithers(t *testing.T) {
+	price := NewMoney(1000, "EUR", map[string]string{"kind": "net"})
+	converted := price.WithAmount(1100).WithCurrency("USD")
+
+	if converted.GetAmount() != 1100 {
+		t.Errorf("Expected amount 1100, got %d", converted.GetAmount())
+	}
+
+	if converted.GetCurrency() != "USD" {
+		t.Errorf("Expected currency USD, got %s", converted.GetCurrency())
+	}
+
+	// The original value is left unchanged
+	if price.GetAmount() != 1000 || price.GetCurrency() != "EUR" {
+		t.Errorf("Original value changed: %d %s", price.GetAmount(), price.GetCurrency())
+	}
+}
+
+func TestMoneyWithersDeepCopy(t *testing.T) {
+	price := NewMoney(1000, "EUR", map[string]string{"kind": "net"})
+	copied := price.WithAmount(2000)
+
+	// Changing the labels of the copy does not affect the original
+	copied.GetLabels()["kind"] = "gross"
+	if price.GetLabels()["kind"] != "net" {
+		t.Errorf("Expected original label net, got %s", price.GetLabels()["kind"])
+	}
+}
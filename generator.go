@@ -4,14 +4,26 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"slices"
+	"sort"
 	"strings"
 	"text/template"
 )
 
+// generatedHeader marks files written by this tool
+const generatedHeader = "// Code generated by constructor. DO NOT EDIT."
+
 // Generator generates constructor code
 type Generator struct {
 	config *GeneratorConfig
 	info   *StructInfo
+	hooks  constructorHooks // Methods called by the constructors, resolved by GenerateFile
+
+	presence *FieldInfo // Presence bitmask field, resolved by generateCode; nil if presence is not tracked
+
+	builders map[string]BuilderInfo // Builders usable by nested builders, keyed by struct, resolved by GenerateFile
+
+	optionNaming string // Option function naming, resolved by GenerateFile
 }
 
 // NewGenerator creates a new generator
@@ -24,13 +36,114 @@ func NewGenerator(config *GeneratorConfig, info *StructInfo) *Generator {
 
 // Generate generates constructor code based on configuration
 func (g *Generator) Generate() (string, error) {
+	return GenerateFile([]*Generator{g})
+}
+
+// GenerateFile generates a single file containing the code of all generators,
+// in the order given. All generators must target structs of the same package.
+func GenerateFile(generators []*Generator) (string, error) {
+	if len(generators) == 0 {
+		return "", fmt.Errorf("no structs to generate")
+	}
+
 	var buf bytes.Buffer
 
 	// Write package declaration
-	buf.WriteString(fmt.Sprintf("package %s\n\n", g.info.PackageName))
-	buf.WriteString("// Code generated by constructor. DO NOT EDIT.\n\n")
+	packageName := generators[0].info.PackageName
+	buf.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	buf.WriteString(generatedHeader + "\n\n")
+
+	// Write imports resolved by type-checked loading; anything else is left to goimports
+	seen := map[ImportInfo]bool{}
+	imports := []ImportInfo{}
+	for _, g := range generators {
+		if g.info.PackageName != packageName {
+			return "", fmt.Errorf("struct %s belongs to package %s, expected %s", g.info.Name, g.info.PackageName, packageName)
+		}
+		for _, imp := range g.info.Imports {
+			if !seen[imp] {
+				seen[imp] = true
+				imports = append(imports, imp)
+			}
+		}
+	}
+	if len(imports) > 0 {
+		sort.Slice(imports, func(i, j int) bool {
+			return imports[i].Path < imports[j].Path
+		})
+		buf.WriteString("import (\n")
+		for _, imp := range imports {
+			if imp.Name != "" {
+				buf.WriteString(fmt.Sprintf("\t%s %q\n", imp.Name, imp.Path))
+			} else {
+				buf.WriteString(fmt.Sprintf("\t%q\n", imp.Path))
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	if err := resolveOptionNaming(generators); err != nil {
+		return "", err
+	}
+	for _, g := range generators {
+		hooks, err := g.resolveHooks()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", g.info.Name, err)
+		}
+		g.hooks = hooks
+	}
+	resolveBuilders(generators)
+
+	for _, g := range generators {
+		code, err := g.generateCode()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", g.info.Name, err)
+		}
+		buf.WriteString(code)
+	}
+
+	// Format and fix imports using goimports
+	code := buf.String()
+
+	// Try to run goimports to fix imports and format
+	cmd := exec.Command("goimports")
+	cmd.Stdin = strings.NewReader(code)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// If goimports fails, return the unformatted code with a warning
+		return code, fmt.Errorf("goimports not available or failed: %w (install with: go install golang.org/x/tools/cmd/goimports@latest)", err)
+	}
+
+	return out.String(), nil
+}
+
+// generateCode generates the constructors and getters of a single struct
+func (g *Generator) generateCode() (string, error) {
+	var buf bytes.Buffer
 
 	fields := g.info.GetFieldsForConstructor()
+	if err := checkAPINames(g.info.Fields); err != nil {
+		return "", err
+	}
+	presence, err := g.resolvePresence(fields)
+	if err != nil {
+		return "", err
+	}
+	g.presence = presence
+
+	// Generate the checks of the field rules shared by all constructors
+	rules, err := g.generateFieldRules()
+	if err != nil {
+		return "", err
+	}
+	if rules != "" {
+		buf.WriteString(rules)
+		buf.WriteString("\n")
+	}
 
 	// Generate constructors based on types
 	for _, constructorType := range g.config.ConstructorTypes {
@@ -51,6 +164,21 @@ func (g *Generator) Generate() (string, error) {
 			buf.WriteString(code)
 			buf.WriteString("\n\n")
 
+		case "stepBuilder":
+			code, err := g.generateStepBuilderConstructor(fields)
+			if err != nil {
+				return "", err
+			}
+			buf.WriteString(code)
+			buf.WriteString("\n\n")
+
+		case "withers":
+			code, err := g.generateWithers(fields)
+			if err != nil {
+				return "", err
+			}
+			buf.WriteString(code)
+
 		case "options":
 			code, err := g.generateOptionsConstructor(fields)
 			if err != nil {
@@ -64,85 +192,123 @@ func (g *Generator) Generate() (string, error) {
 		}
 	}
 
+	if g.presence != nil {
+		buf.WriteString(g.generatePresence(fields))
+	}
+
 	// Generate getters if requested
 	if g.config.WithGetter {
 		getterFields := g.info.GetFieldsForGetter()
-		code := g.generateGetters(getterFields)
+		code, err := g.generateGetters(getterFields)
+		if err != nil {
+			return "", err
+		}
 		buf.WriteString(code)
 		buf.WriteString("\n")
 	}
 
-	// Format and fix imports using goimports
-	code := buf.String()
-
-	// Try to run goimports to fix imports and format
-	cmd := exec.Command("goimports")
-	cmd.Stdin = strings.NewReader(code)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		// If goimports fails, return the unformatted code with a warning
-		return code, fmt.Errorf("goimports not available or failed: %w (install with: go install golang.org/x/tools/cmd/goimports@latest)", err)
+	// Generate setters if requested
+	if g.config.WithSetter {
+		code, err := g.generateSetters(fields)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(code)
+		buf.WriteString("\n")
 	}
 
-	return out.String(), nil
+	return buf.String(), nil
 }
 
 // generateAllArgsConstructor generates a constructor with all fields as parameters
 func (g *Generator) generateAllArgsConstructor(fields []FieldInfo) (string, error) {
-	tmpl := `// New{{.StructName}} creates a new {{.StructName}}
-func New{{.StructName}}({{.Params}}) {{.ReturnType}} {
-	{{.VarDecl}}{{.StructName}}{
+	tmpl := `// New{{.StructName}} creates a new {{.StructName}}{{if .ErrorConditions}}, returning an error if {{.ErrorConditions}}{{end}}
+{{.StructDoc}}{{.ParamDocs}}func New{{.StructName}}{{.TypeParams}}({{.Params}}) {{.ReturnType}} {
+	{{.Defaults}}{{.VarDecl}}{{.TypeName}}{
 		{{.FieldAssignments}}
-	}{{.InitCall}}{{if .ReturnValue}}
-	return {{.ReturnValue}}{{end}}
-}`
+	}
+{{.InitCall}}{{if .ReturnValue}}	return {{.ReturnValue}}
+{{end}}}`
+
+	defaults, err := g.fieldDefaults()
+	if err != nil {
+		return "", err
+	}
 
 	params := []string{}
-	assignments := []string{}
+	values := []fieldValue{}
+	defaultChecks := ""
 
+	if g.hooks.initContext {
+		params = append(params, g.contextParam())
+	}
+
+	if g.config.OmitDeprecated {
+		fields = slices.DeleteFunc(slices.Clone(fields), isDeprecated)
+	}
 	for _, field := range fields {
-		paramName := toLowerCamelCase(field.Name)
+		paramName := toLowerCamelCase(field.APIName())
 		params = append(params, fmt.Sprintf("%s %s", paramName, field.Type))
-		assignments = append(assignments, fmt.Sprintf("%s: %s,", field.Name, paramName))
+		values = append(values, fieldValue{field, paramName})
+
+		// Replace zero arguments with the field default
+		if expr, ok := defaults[field.Name]; ok && g.config.AllArgsDefaults {
+			defaultChecks += fmt.Sprintf("if %s {\n\t\t%s = %s\n\t}\n\t", zeroCheck(field, paramName), paramName, expr)
+		}
+	}
+
+	// Fields without a constructor parameter are set to their default directly
+	for _, field := range g.info.Fields {
+		omitted := g.config.OmitDeprecated && isDeprecated(field)
+		if expr, ok := defaults[field.Name]; ok && (field.SkipSetter || omitted) {
+			values = append(values, fieldValue{field, expr})
+		}
 	}
 
-	returnType := "*" + g.info.Name
+	for _, field := range fields {
+		if g.hooks.initContext && toLowerCamelCase(field.APIName()) == "ctx" {
+			return "", fmt.Errorf("%s: field %s clashes with the ctx parameter of the init method", field.Pos, field.Name)
+		}
+	}
+
+	returnType := "*" + g.typeName()
 	varDecl := "return &"
 	returnValue := ""
 
 	if g.config.ReturnValue {
-		returnType = g.info.Name
+		returnType = g.typeName()
 		varDecl = "return "
 		returnValue = ""
 	}
 
-	// Handle init function
-	initCall := ""
-	if g.config.InitFunc != "" {
+	// Handle init and validate methods
+	initCall := g.hooks.calls(g.valuePointer(), g.zeroResult())
+	if initCall != "" {
 		if g.config.ReturnValue {
 			varDecl = "v := "
-			returnValue = "v"
 		} else {
 			varDecl = "v := &"
-			returnValue = "v"
 		}
-		initCall = fmt.Sprintf("\n\tv.%s()", g.config.InitFunc)
-	} else {
-		// No init function, so returnValue stays empty for direct return
-		returnValue = ""
+		returnValue = "v"
+	}
+	if g.hooks.returnsError() {
+		returnType = "(" + returnType + ", error)"
+		returnValue += ", nil"
 	}
 
 	data := map[string]string{
 		"StructName":       g.info.Name,
+		"TypeName":         g.typeName(),
+		"TypeParams":       g.info.TypeParamsDecl(),
 		"Params":           strings.Join(params, ", "),
+		"StructDoc":        g.structDocComment(),
+		"ParamDocs":        paramDocs(fields),
+		"Defaults":         defaultChecks,
 		"ReturnType":       returnType,
 		"VarDecl":          varDecl,
-		"FieldAssignments": strings.Join(assignments, "\n\t\t"),
+		"FieldAssignments": strings.Join(g.literalElements(values), "\n\t\t"),
 		"InitCall":         initCall,
+		"ErrorConditions":  g.errorConditions(""),
 		"ReturnValue":      returnValue,
 	}
 
@@ -163,148 +329,782 @@ func New{{.StructName}}({{.Params}}) {{.ReturnType}} {
 func (g *Generator) generateBuilderConstructor(fields []FieldInfo) (string, error) {
 	var buf bytes.Buffer
 
+	defaults, err := g.fieldDefaults()
+	if err != nil {
+		return "", err
+	}
+
 	builderName := g.info.Name + "Builder"
+	builderType := builderName + g.info.TypeArgs()
 	prefix := g.config.SetterPrefix
 	if prefix == "" {
 		prefix = "" // No prefix by default, methods named after fields
 	}
 
+	carried := g.carriedFields()
+	if err := g.checkSetFlags(fields, carried); err != nil {
+		return "", err
+	}
+
 	// Generate builder struct
 	buf.WriteString(fmt.Sprintf("// %s is a builder for %s\n", builderName, g.info.Name))
-	buf.WriteString(fmt.Sprintf("type %s struct {\n", builderName))
+	buf.WriteString(g.structDocComment())
+	buf.WriteString(fmt.Sprintf("type %s%s struct {\n", builderName, g.info.TypeParamsDecl()))
 	for _, field := range fields {
 		buf.WriteString(fmt.Sprintf("\t%s %s\n", toLowerCamelCase(field.Name), field.Type))
 	}
+	// Track which required fields were set, so that explicit zero values count
+	for _, field := range requiredFields(fields) {
+		buf.WriteString(fmt.Sprintf("\t%sSet bool\n", toLowerCamelCase(field.Name)))
+	}
+	// Fields without setters are carried over from the instance a builder was created from
+	for _, field := range carried {
+		buf.WriteString(fmt.Sprintf("\t%s %s\n", toLowerCamelCase(field.Name), field.Type))
+	}
+	if g.presence != nil {
+		buf.WriteString(fmt.Sprintf("\t%s %s\n", g.presence.Name, g.presence.Type))
+	}
 	buf.WriteString("}\n\n")
 
-	// Generate builder constructor
+	// Generate builder constructor, starting from the field defaults
+	initialValues := []string{}
+	for _, field := range append(slices.Clone(fields), carried...) {
+		if expr, ok := defaults[field.Name]; ok {
+			initialValues = append(initialValues, fmt.Sprintf("%s: %s,", toLowerCamelCase(field.Name), expr))
+		}
+	}
 	buf.WriteString(fmt.Sprintf("// New%s creates a new %s\n", builderName, builderName))
-	buf.WriteString(fmt.Sprintf("func New%s() *%s {\n", builderName, builderName))
-	buf.WriteString(fmt.Sprintf("\treturn &%s{}\n", builderName))
+	buf.WriteString(fmt.Sprintf("func New%s%s() *%s {\n", builderName, g.info.TypeParamsDecl(), builderType))
+	buf.WriteString(fmt.Sprintf("\treturn &%s{%s}\n", builderType, compositeFields(initialValues, 1)))
 	buf.WriteString("}\n\n")
 
+	helpers, err := g.collectionHelpers(fields)
+	if err != nil {
+		return "", err
+	}
+	if g.config.ToBuilder {
+		buf.WriteString(g.generateToBuilder(fields, carried, helpers))
+	}
+
 	// Generate setter methods
 	for _, field := range fields {
-		methodName := prefix + toUpperCamelCase(field.Name)
-		paramName := toLowerCamelCase(field.Name)
+		methodName := prefix + toUpperCamelCase(field.APIName())
+		paramName := toLowerCamelCase(field.APIName())
 		fieldName := toLowerCamelCase(field.Name)
 
 		buf.WriteString(fmt.Sprintf("// %s sets the %s field\n", methodName, field.Name))
+		buf.WriteString(docComment(field, ""))
 		buf.WriteString(fmt.Sprintf("func (b *%s) %s(%s %s) *%s {\n",
-			builderName, methodName, paramName, field.Type, builderName))
+			builderType, methodName, paramName, field.Type, builderType))
 		buf.WriteString(fmt.Sprintf("\tb.%s = %s\n", fieldName, paramName))
+		if field.Required {
+			buf.WriteString(fmt.Sprintf("\tb.%sSet = true\n", fieldName))
+		}
+		buf.WriteString(g.presenceMark("\t", "b", field))
 		buf.WriteString("\treturn b\n")
 		buf.WriteString("}\n\n")
 	}
 
+	buf.WriteString(g.generateBuilderCollectionHelpers(helpers, builderType))
+	if g.config.NestedBuilders {
+		buf.WriteString(g.generateNestedBuilders(fields, builderType))
+	}
+
 	// Generate Build method
-	returnType := "*" + g.info.Name
+	returnType := "*" + g.typeName()
 	if g.config.ReturnValue {
-		returnType = g.info.Name
+		returnType = g.typeName()
 	}
 
-	buf.WriteString(fmt.Sprintf("// Build builds the %s\n", g.info.Name))
-	buf.WriteString(fmt.Sprintf("func (b *%s) Build() %s {\n", builderName, returnType))
+	required := requiredFields(fields)
+	missing := ""
+	if len(required) > 0 {
+		missing = "a required field was not set"
+	}
+	if conditions := g.errorConditions(missing); conditions != "" {
+		buf.WriteString(fmt.Sprintf("// Build builds the %s, returning an error if %s\n", g.info.Name, conditions))
+		buf.WriteString(fmt.Sprintf("func (b *%s) Build(%s) (%s, error) {\n", builderType, g.contextParam(), returnType))
+	} else {
+		buf.WriteString(fmt.Sprintf("// Build builds the %s\n", g.info.Name))
+		buf.WriteString(fmt.Sprintf("func (b *%s) Build(%s) %s {\n", builderType, g.contextParam(), returnType))
+	}
+	buf.WriteString(g.missingFieldsCheck(required, func(field FieldInfo) string {
+		return fmt.Sprintf("!b.%sSet", toLowerCamelCase(field.Name))
+	}))
 
+	values := []fieldValue{}
+	for _, field := range append(slices.Clone(fields), carried...) {
+		values = append(values, fieldValue{field, "b." + toLowerCamelCase(field.Name)})
+	}
+	if g.presence != nil {
+		values = append(values, fieldValue{*g.presence, "b." + g.presence.Name})
+	}
+	// Carried fields start from their defaults in the builder instead
+	for _, field := range g.info.Fields {
+		if expr, ok := defaults[field.Name]; ok && field.SkipSetter && !g.config.ToBuilder {
+			values = append(values, fieldValue{field, expr})
+		}
+	}
 	if g.config.ReturnValue {
-		buf.WriteString(fmt.Sprintf("\tv := %s{\n", g.info.Name))
+		buf.WriteString(fmt.Sprintf("\tv := %s{%s}\n", g.typeName(), compositeFields(g.literalElements(values), 1)))
 	} else {
-		buf.WriteString(fmt.Sprintf("\tv := &%s{\n", g.info.Name))
+		buf.WriteString(fmt.Sprintf("\tv := &%s{%s}\n", g.typeName(), compositeFields(g.literalElements(values), 1)))
 	}
 
-	for _, field := range fields {
-		paramName := toLowerCamelCase(field.Name)
-		buf.WriteString(fmt.Sprintf("\t\t%s: b.%s,\n", field.Name, paramName))
-	}
-	buf.WriteString("\t}\n")
+	// Handle init and validate methods
+	buf.WriteString(g.hooks.calls(g.valuePointer(), g.zeroResult()))
 
-	// Handle init function
-	if g.config.InitFunc != "" {
-		buf.WriteString(fmt.Sprintf("\tv.%s()\n", g.config.InitFunc))
+	if missing != "" || g.hooks.returnsError() {
+		buf.WriteString("\treturn v, nil\n")
+	} else {
+		buf.WriteString("\treturn v\n")
 	}
-
-	buf.WriteString("\treturn v\n")
 	buf.WriteString("}\n")
 
 	return buf.String(), nil
 }
 
+// carriedFields returns the fields without setters that builders carry over
+// from the instance they were created from, with -toBuilder. Skipped fields
+// are left out, as they may hold state that must not be copied, e.g., locks.
+func (g *Generator) carriedFields() []FieldInfo {
+	result := []FieldInfo{}
+	if !g.config.ToBuilder {
+		return result
+	}
+	for _, field := range g.info.Fields {
+		if !field.Skip && field.SkipSetter {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// generateToBuilder generates NewXBuilderFrom, creating a builder initialized
+// with the fields of an existing instance, and the ToBuilder method calling it.
+// Fields with collection helpers are copied, so that adding items leaves the
+// instance unchanged.
+func (g *Generator) generateToBuilder(fields, carried []FieldInfo, helpers []collectionHelper) string {
+	var buf bytes.Buffer
+
+	builderName := g.info.Name + "Builder"
+	builderType := builderName + g.info.TypeArgs()
+
+	copied := map[string]bool{}
+	for _, h := range helpers {
+		copied[h.field.Name] = true
+	}
+
+	values := []string{}
+	for _, field := range append(slices.Clone(fields), carried...) {
+		value := "v." + field.Path()
+		if clone := cloneExpr(field, value); clone != "" && copied[field.Name] {
+			value = clone
+		}
+		values = append(values, fmt.Sprintf("%s: %s,", toLowerCamelCase(field.Name), value))
+	}
+	// Required fields hold the values they were built with, so they count as set
+	for _, field := range requiredFields(fields) {
+		values = append(values, fmt.Sprintf("%sSet: true,", toLowerCamelCase(field.Name)))
+	}
+	if g.presence != nil {
+		values = append(values, fmt.Sprintf("%s: v.%s,", g.presence.Name, g.presence.Name))
+	}
+
+	buf.WriteString(fmt.Sprintf("// New%sFrom creates a new %s initialized with the fields of v\n", builderName, builderName))
+	buf.WriteString(fmt.Sprintf("func New%sFrom%s(v *%s) *%s {\n", builderName, g.info.TypeParamsDecl(), g.typeName(), builderType))
+	buf.WriteString(fmt.Sprintf("\treturn &%s{%s}\n", builderType, compositeFields(values, 1)))
+	buf.WriteString("}\n\n")
+
+	receiverName := g.receiverName()
+	buf.WriteString(fmt.Sprintf("// ToBuilder returns a new %s initialized with the fields of %s\n", builderName, receiverName))
+	buf.WriteString(fmt.Sprintf("func (%s *%s) ToBuilder() *%s {\n", receiverName, g.typeName(), builderType))
+	buf.WriteString(fmt.Sprintf("\treturn New%sFrom(%s)\n", builderName, receiverName))
+	buf.WriteString("}\n\n")
+
+	return buf.String()
+}
+
 // generateOptionsConstructor generates a functional options pattern constructor
 func (g *Generator) generateOptionsConstructor(fields []FieldInfo) (string, error) {
 	var buf bytes.Buffer
 
-	optionType := g.info.Name + "Option"
-	returnType := "*" + g.info.Name
+	defaults, err := g.fieldDefaults()
+	if err != nil {
+		return "", err
+	}
+
+	optionName := g.info.Name + "Option"
+	optionType := optionName + g.info.TypeArgs()
+	typeParams := g.info.TypeParamsDecl()
+	returnType := "*" + g.typeName()
 	if g.config.ReturnValue {
-		returnType = g.info.Name
+		returnType = g.typeName()
 	}
 
-	// Generate option type
-	buf.WriteString(fmt.Sprintf("// %s is a functional option for configuring %s\n", optionType, g.info.Name))
-	buf.WriteString(fmt.Sprintf("type %s func(*%s)\n\n", optionType, g.info.Name))
+	buf.WriteString(g.generateOptionNamespace())
 
-	// Generate option functions
-	for _, field := range fields {
-		optionName := "With" + toUpperCamelCase(field.Name)
-		paramName := toLowerCamelCase(field.Name)
+	errorStyle := g.config.OptionStyle == optionStyleError
+	applyCall := "opt(v)"
+	if g.config.OptionStyle == optionStyleInterface {
+		buf.WriteString(g.generateInterfaceOptions(fields))
+		applyCall = "opt.apply(v)"
+	} else {
+		// Generate option type
+		optionResult := ""
+		if errorStyle {
+			optionResult = " error"
+			buf.WriteString(fmt.Sprintf("// %s is a functional option for configuring %s, returning an error if its value is invalid\n", optionName, g.info.Name))
+		} else {
+			buf.WriteString(fmt.Sprintf("// %s is a functional option for configuring %s\n", optionName, g.info.Name))
+		}
+		buf.WriteString(g.structDocComment())
+		buf.WriteString(fmt.Sprintf("type %s%s func(*%s)%s\n\n", optionName, typeParams, g.typeName(), optionResult))
 
-		buf.WriteString(fmt.Sprintf("// %s sets the %s field\n", optionName, field.Name))
-		buf.WriteString(fmt.Sprintf("func %s(%s %s) %s {\n", optionName, paramName, field.Type, optionType))
-		buf.WriteString(fmt.Sprintf("\treturn func(s *%s) {\n", g.info.Name))
-		buf.WriteString(fmt.Sprintf("\t\ts.%s = %s\n", field.Name, paramName))
-		buf.WriteString("\t}\n")
-		buf.WriteString("}\n\n")
+		// Generate option functions
+		for _, field := range fields {
+			paramName := toLowerCamelCase(field.APIName())
+
+			checks := ""
+			if errorStyle {
+				checks, err = g.fieldRuleChecks(field, paramName)
+				if err != nil {
+					return "", err
+				}
+			}
+
+			if checks != "" {
+				buf.WriteString(fmt.Sprintf("// %s sets the %s field, failing if the value violates the rules of its tag\n", g.optionFuncName(field), field.Name))
+			} else {
+				buf.WriteString(fmt.Sprintf("// %s sets the %s field\n", g.optionFuncName(field), field.Name))
+			}
+			buf.WriteString(docComment(field, ""))
+			buf.WriteString(fmt.Sprintf("%s(%s %s) %s {\n", g.optionFuncDecl(field), paramName, field.Type, optionType))
+			buf.WriteString(fmt.Sprintf("\treturn func(s *%s)%s {\n", g.typeName(), optionResult))
+			for _, line := range strings.SplitAfter(checks, "\n") {
+				if line != "" {
+					buf.WriteString("\t" + line)
+				}
+			}
+			buf.WriteString(fmt.Sprintf("\t\ts.%s = %s\n", field.Path(), paramName))
+			buf.WriteString(g.presenceMark("\t\t", "s", field))
+			if errorStyle {
+				buf.WriteString("\t\treturn nil\n")
+			}
+			buf.WriteString("\t}\n")
+			buf.WriteString("}\n\n")
+		}
 	}
 
-	// Generate constructor with options
-	buf.WriteString(fmt.Sprintf("// New%sWithOptions creates a new %s with functional options\n", g.info.Name, g.info.Name))
-	buf.WriteString(fmt.Sprintf("func New%sWithOptions(opts ...%s) %s {\n", g.info.Name, optionType, returnType))
+	helpers, err := g.collectionHelpers(fields)
+	if err != nil {
+		return "", err
+	}
+	buf.WriteString(g.generateOptionCollectionHelpers(helpers))
 
-	if g.config.ReturnValue {
-		buf.WriteString(fmt.Sprintf("\tv := &%s{}\n", g.info.Name))
+	// Generate constructor with options
+	required := requiredFields(fields)
+	missing := ""
+	if len(required) > 0 {
+		missing = "a required field was left at its zero value"
+	}
+	params := fmt.Sprintf("opts ...%s", optionType)
+	if g.hooks.initContext {
+		params = g.contextParam() + ", " + params
+	}
+	conditions := g.errorConditions(missing)
+	switch {
+	case errorStyle && conditions != "":
+		conditions = "an option fails or " + conditions
+	case errorStyle:
+		conditions = "an option fails"
+	}
+	if conditions != "" {
+		buf.WriteString(fmt.Sprintf("// New%sWithOptions creates a new %s with functional options,\n", g.info.Name, g.info.Name))
+		buf.WriteString(fmt.Sprintf("// returning an error if %s\n", conditions))
+		buf.WriteString(g.structDocComment())
+		buf.WriteString(fmt.Sprintf("func New%sWithOptions%s(%s) (%s, error) {\n", g.info.Name, typeParams, params, returnType))
 	} else {
-		buf.WriteString(fmt.Sprintf("\tv := &%s{}\n", g.info.Name))
+		buf.WriteString(fmt.Sprintf("// New%sWithOptions creates a new %s with functional options\n", g.info.Name, g.info.Name))
+		buf.WriteString(g.structDocComment())
+		buf.WriteString(fmt.Sprintf("func New%sWithOptions%s(%s) %s {\n", g.info.Name, typeParams, params, returnType))
 	}
 
-	buf.WriteString("\tfor _, opt := range opts {\n")
-	buf.WriteString("\t\topt(v)\n")
-	buf.WriteString("\t}\n")
+	// Defaults are set before the options run, so options can override them
+	initialValues := []fieldValue{}
+	for _, field := range g.info.Fields {
+		if expr, ok := defaults[field.Name]; ok {
+			initialValues = append(initialValues, fieldValue{field, expr})
+		}
+	}
+	buf.WriteString(fmt.Sprintf("\tv := &%s{%s}\n", g.typeName(), compositeFields(g.literalElements(initialValues), 1)))
 
-	// Handle init function
-	if g.config.InitFunc != "" {
-		buf.WriteString(fmt.Sprintf("\tv.%s()\n", g.config.InitFunc))
+	if errorStyle {
+		// Run every option, so that all invalid values are reported at once
+		buf.WriteString("\tvar errs []error\n")
+		buf.WriteString("\tfor _, opt := range opts {\n")
+		buf.WriteString("\t\tif err := opt(v); err != nil {\n")
+		buf.WriteString("\t\t\terrs = append(errs, err)\n")
+		buf.WriteString("\t\t}\n")
+		buf.WriteString("\t}\n")
+		buf.WriteString("\tif err := errors.Join(errs...); err != nil {\n")
+		buf.WriteString(fmt.Sprintf("\t\treturn %s, err\n", g.zeroResult()))
+		buf.WriteString("\t}\n")
+	} else {
+		buf.WriteString("\tfor _, opt := range opts {\n")
+		buf.WriteString(fmt.Sprintf("\t\t%s\n", applyCall))
+		buf.WriteString("\t}\n")
 	}
 
+	// Without presence tracking, options cannot record whether they ran, so
+	// required fields must be non-zero
+	buf.WriteString(g.missingFieldsCheck(required, func(field FieldInfo) string {
+		if g.presence != nil {
+			return fmt.Sprintf("v.%s&%s == 0", g.presence.Name, g.presenceBit(field))
+		}
+		return zeroCheck(field, "v."+field.Path())
+	}))
+
+	// Handle init and validate methods
+	buf.WriteString(g.hooks.calls("v", g.zeroResult()))
+
+	result := "v"
 	if g.config.ReturnValue {
-		buf.WriteString("\treturn *v\n")
-	} else {
-		buf.WriteString("\treturn v\n")
+		result = "*v"
 	}
+	if conditions != "" {
+		result += ", nil"
+	}
+	buf.WriteString(fmt.Sprintf("\treturn %s\n", result))
 	buf.WriteString("}\n")
 
 	return buf.String(), nil
 }
 
+// Getter naming styles selected with -getterStyle
+const (
+	getterStyleGet   = "get"   // GetName
+	getterStylePlain = "plain" // Name, as recommended by Effective Go
+)
+
+// getterStyles lists the valid values of -getterStyle
+var getterStyles = []string{getterStyleGet, getterStylePlain}
+
+// receiverKinds lists the valid values of -receiver
+var receiverKinds = []string{"pointer", "value"}
+
 // generateGetters generates getter methods for all fields
-func (g *Generator) generateGetters(fields []FieldInfo) string {
+func (g *Generator) generateGetters(fields []FieldInfo) (string, error) {
 	var buf bytes.Buffer
 
+	if err := g.checkGetterNames(fields); err != nil {
+		return "", err
+	}
+
+	receiverName := g.receiverName()
+	receiverType := "*" + g.typeName()
+	if g.config.Receiver == "value" {
+		receiverType = g.typeName()
+	}
+
 	for _, field := range fields {
 		if !field.Exported {
-			getterName := "Get" + toUpperCamelCase(field.Name)
-			receiverName := strings.ToLower(string(g.info.Name[0]))
+			getterName := g.getterName(field)
 
 			buf.WriteString(fmt.Sprintf("// %s returns the %s field\n", getterName, field.Name))
-			buf.WriteString(fmt.Sprintf("func (%s *%s) %s() %s {\n",
-				receiverName, g.info.Name, getterName, field.Type))
-			buf.WriteString(fmt.Sprintf("\treturn %s.%s\n", receiverName, field.Name))
+			buf.WriteString(docComment(field, ""))
+			buf.WriteString(fmt.Sprintf("func (%s %s) %s() %s {\n",
+				receiverName, receiverType, getterName, field.Type))
+			buf.WriteString(fmt.Sprintf("\treturn %s.%s\n", receiverName, field.Path()))
 			buf.WriteString("}\n\n")
 		}
 	}
 
+	return buf.String(), nil
+}
+
+// getterName returns the name of the getter of field: the name given by a
+// getter=... tag, or one derived from the field according to -getterStyle
+func (g *Generator) getterName(field FieldInfo) string {
+	if field.GetterName != "" {
+		return field.GetterName
+	}
+	if g.config.GetterStyle == getterStylePlain {
+		return toUpperCamelCase(field.APIName())
+	}
+	return "Get" + toUpperCamelCase(field.APIName())
+}
+
+// checkGetterNames reports getters that would clash with a field, a method
+// declared on the struct outside generated files, or another getter
+func (g *Generator) checkGetterNames(fields []FieldInfo) error {
+	taken := map[string]string{}
+	for _, field := range g.info.Fields {
+		taken[field.Name] = "field " + field.Name
+	}
+	for _, method := range g.info.Methods {
+		if !method.Generated {
+			taken[method.Name] = "method " + method.Name
+		}
+	}
+
+	for _, field := range fields {
+		if field.Exported {
+			continue
+		}
+		name := g.getterName(field)
+		if other, ok := taken[name]; ok {
+			return fmt.Errorf("%s: getter %s of field %s clashes with %s of %s", field.Pos, name, field.Name, other, g.info.Name)
+		}
+		taken[name] = "the getter of field " + field.Name
+	}
+	return nil
+}
+
+// generateSetters generates setter methods for the private fields. Fluent
+// setters return the receiver. With -setterChecks, setters of required fields
+// reject zero values and setters of fields with rules check them; these
+// setters return an error instead of the receiver.
+func (g *Generator) generateSetters(fields []FieldInfo) (string, error) {
+	var buf bytes.Buffer
+	receiverName := g.receiverName()
+
+	for _, field := range fields {
+		if field.Exported {
+			continue
+		}
+		setterName := "Set" + toUpperCamelCase(field.APIName())
+		paramName := toLowerCamelCase(field.APIName())
+		if paramName == receiverName {
+			return "", fmt.Errorf("%s: field %s clashes with the receiver %s of the setter methods", field.Pos, field.Name, receiverName)
+		}
+
+		checks := ""
+		if g.config.SetterChecks {
+			if field.Required {
+				checks += fmt.Sprintf("\tif %s {\n", zeroCheck(field, paramName))
+				checks += fmt.Sprintf("\t\treturn errors.New(\"missing required fields of %s: %s\")\n", g.info.Name, field.APIName())
+				checks += "\t}\n"
+			}
+			rules, err := g.fieldRuleChecks(field, paramName)
+			if err != nil {
+				return "", err
+			}
+			checks += rules
+		}
+
+		switch {
+		case checks != "":
+			buf.WriteString(fmt.Sprintf("// %s sets the %s field, returning an error if the value is invalid\n", setterName, field.Name))
+			buf.WriteString(docComment(field, ""))
+			buf.WriteString(fmt.Sprintf("func (%s *%s) %s(%s %s) error {\n",
+				receiverName, g.typeName(), setterName, paramName, field.Type))
+			buf.WriteString(checks)
+			buf.WriteString(fmt.Sprintf("\t%s.%s = %s\n", receiverName, field.Path(), paramName))
+			buf.WriteString(g.presenceMark("\t", receiverName, field))
+			buf.WriteString("\treturn nil\n")
+		case g.config.FluentSetter:
+			buf.WriteString(fmt.Sprintf("// %s sets the %s field and returns %s\n", setterName, field.Name, receiverName))
+			buf.WriteString(docComment(field, ""))
+			buf.WriteString(fmt.Sprintf("func (%s *%s) %s(%s %s) *%s {\n",
+				receiverName, g.typeName(), setterName, paramName, field.Type, g.typeName()))
+			buf.WriteString(fmt.Sprintf("\t%s.%s = %s\n", receiverName, field.Path(), paramName))
+			buf.WriteString(g.presenceMark("\t", receiverName, field))
+			buf.WriteString(fmt.Sprintf("\treturn %s\n", receiverName))
+		default:
+			buf.WriteString(fmt.Sprintf("// %s sets the %s field\n", setterName, field.Name))
+			buf.WriteString(docComment(field, ""))
+			buf.WriteString(fmt.Sprintf("func (%s *%s) %s(%s %s) {\n",
+				receiverName, g.typeName(), setterName, paramName, field.Type))
+			buf.WriteString(fmt.Sprintf("\t%s.%s = %s\n", receiverName, field.Path(), paramName))
+			buf.WriteString(g.presenceMark("\t", receiverName, field))
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.String(), nil
+}
+
+// checkAPINames reports fields that would be exposed under the same name in
+// generated code, which happens when a name=... tag override clashes with
+// another field
+func checkAPINames(fields []FieldInfo) error {
+	seen := map[string]FieldInfo{}
+	for _, field := range fields {
+		if field.Skip {
+			continue
+		}
+		key := toUpperCamelCase(field.APIName())
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("%s: field %s is exposed as %s, which clashes with field %s", field.Pos, field.Name, key, other.Name)
+		}
+		seen[key] = field
+	}
+	return nil
+}
+
+// checkSetFlags reports required fields whose flag tracking whether they were
+// set, e.g., "nameSet", clashes with another field of the builder
+func (g *Generator) checkSetFlags(fields, carried []FieldInfo) error {
+	taken := map[string]string{}
+	for _, field := range append(slices.Clone(fields), carried...) {
+		taken[toLowerCamelCase(field.Name)] = field.Name
+	}
+	if g.presence != nil {
+		taken[g.presence.Name] = g.presence.Name
+	}
+	for _, field := range requiredFields(fields) {
+		flag := toLowerCamelCase(field.Name) + "Set"
+		if other, ok := taken[flag]; ok {
+			return fmt.Errorf("%s: builder flag %s of required field %s clashes with field %s", field.Pos, flag, field.Name, other)
+		}
+	}
+	return nil
+}
+
+// requiredFields returns the fields tagged constructor:"required"
+func requiredFields(fields []FieldInfo) []FieldInfo {
+	result := []FieldInfo{}
+	for _, field := range fields {
+		if field.Required {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// missingFieldsCheck generates the statements returning an error that lists the
+// required fields for which the condition given by isMissing holds.
+// Returns an empty string when there are no required fields.
+func (g *Generator) missingFieldsCheck(required []FieldInfo, isMissing func(FieldInfo) string) string {
+	if len(required) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\tvar missing []string\n")
+	for _, field := range required {
+		buf.WriteString(fmt.Sprintf("\tif %s {\n", isMissing(field)))
+		buf.WriteString(fmt.Sprintf("\t\tmissing = append(missing, %q)\n", field.APIName()))
+		buf.WriteString("\t}\n")
+	}
+	buf.WriteString("\tif len(missing) > 0 {\n")
+	buf.WriteString(fmt.Sprintf("\t\treturn %s, fmt.Errorf(\"missing required fields of %s: %%s\", strings.Join(missing, \", \"))\n",
+		g.zeroResult(), g.info.Name))
+	buf.WriteString("\t}\n")
+	return buf.String()
+}
+
+// errorConditions describes when the constructors return an error, e.g.,
+// "a required field was not set or Validate fails", or returns an empty string
+// if they cannot fail. missing describes missing required fields, if any.
+func (g *Generator) errorConditions(missing string) string {
+	conditions := []string{}
+	if missing != "" {
+		conditions = append(conditions, missing)
+	}
+	if g.hooks.ruleFunc != "" {
+		conditions = append(conditions, "a field violates the rules of its tag")
+	}
+
+	failing := []string{}
+	if g.hooks.initError {
+		failing = append(failing, g.hooks.initFunc)
+	}
+	if g.hooks.validateFunc != "" {
+		failing = append(failing, g.hooks.validateFunc)
+	}
+	if len(failing) > 0 {
+		conditions = append(conditions, strings.Join(failing, " or ")+" fails")
+	}
+
+	return strings.Join(conditions, " or ")
+}
+
+// contextParam returns the context parameter of the constructors, needed when
+// the init method takes a context.Context, or an empty string
+func (g *Generator) contextParam() string {
+	if g.hooks.initContext {
+		return "ctx context.Context"
+	}
+	return ""
+}
+
+// zeroResult returns the value returned alongside an error by constructors
+func (g *Generator) zeroResult() string {
+	if g.config.ReturnValue {
+		return g.typeName() + "{}"
+	}
+	return "nil"
+}
+
+// valuePointer returns the expression pointing to the new struct v, which the
+// allArgs constructor and Build methods declare as a value with -returnValue
+func (g *Generator) valuePointer() string {
+	if g.config.ReturnValue {
+		return "&v"
+	}
+	return "v"
+}
+
+// fieldDefaults returns the Go expressions of the field defaults declared with
+// constructor:"default=...", keyed by field name
+func (g *Generator) fieldDefaults() (map[string]string, error) {
+	defaults := map[string]string{}
+	for _, field := range g.info.Fields {
+		if field.Skip {
+			continue
+		}
+		expr, ok, err := defaultExpr(field)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			defaults[field.Name] = expr
+		}
+	}
+	return defaults, nil
+}
+
+// docComment returns the doc comment of field as comment lines at the given
+// indentation, continuing the comment of a generated declaration after an
+// empty comment line, or an empty string if the field is undocumented
+func docComment(field FieldInfo, indent string) string {
+	return continuedComment(field.Doc, indent)
+}
+
+// structDocComment returns the doc comment of the struct as comment lines
+// continuing the comment of a generated constructor or type, or an empty
+// string if the struct is undocumented
+func (g *Generator) structDocComment() string {
+	return continuedComment(g.info.Doc, "")
+}
+
+// continuedComment returns doc as comment lines at the given indentation
+// after an empty comment line, or an empty string if doc is empty
+func continuedComment(doc, indent string) string {
+	if doc == "" {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteString(indent + "//\n")
+	for _, line := range strings.Split(doc, "\n") {
+		buf.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
 	return buf.String()
 }
 
+// deprecatedComment returns the Deprecated: notice of field as comment lines
+// continuing the comment of a generated declaration that does not carry the
+// whole doc of the field, or an empty string if the field is not deprecated
+func deprecatedComment(field FieldInfo, indent string) string {
+	if field.Deprecated == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s//\n%s// Deprecated: %s\n", indent, indent, field.Deprecated)
+}
+
+// isDeprecated reports whether the doc of field has a Deprecated: paragraph
+func isDeprecated(field FieldInfo) bool {
+	return field.Deprecated != ""
+}
+
+// paramDocs returns a comment section listing the parameters of the all args
+// constructor set from documented fields with their doc, or an empty string
+// if no field is documented
+func paramDocs(fields []FieldInfo) string {
+	items := []string{}
+	for _, field := range fields {
+		if field.Doc == "" {
+			continue
+		}
+		lines := []string{}
+		for _, paragraph := range strings.Split(field.Doc, "\n\n") {
+			// A Deprecated: paragraph would deprecate the constructor itself
+			if strings.HasPrefix(paragraph, "Deprecated: ") {
+				continue
+			}
+			for _, line := range strings.Split(paragraph, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					lines = append(lines, line)
+				}
+			}
+		}
+		if field.Deprecated != "" {
+			lines = append(lines, "(deprecated: "+field.Deprecated+")")
+		}
+		items = append(items, fmt.Sprintf("//   - %s: %s\n", toLowerCamelCase(field.APIName()), strings.Join(lines, "\n//     ")))
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	return "//\n// Parameters:\n" + strings.Join(items, "")
+}
+
+// fieldValue is the value of a field in a composite literal of the struct
+type fieldValue struct {
+	field FieldInfo
+	expr  string
+}
+
+// literalElements returns the elements of a composite literal of the struct
+// setting the fields to their values, e.g., "Name: name,". Fields of flattened
+// embedded structs are set in a nested literal of their embedded field.
+func (g *Generator) literalElements(values []fieldValue) []string {
+	elements := []string{}
+	nested := map[string]int{} // Index of the element of each embedded field
+	for _, value := range values {
+		element := fmt.Sprintf("%s: %s,", value.field.Name, value.expr)
+		parent := value.field.Parent
+		if parent == "" {
+			elements = append(elements, element)
+			continue
+		}
+		i, ok := nested[parent]
+		if !ok {
+			i = len(elements)
+			nested[parent] = i
+			elements = append(elements, fmt.Sprintf("%s: %s{\n", parent, g.embeddedType(parent)))
+		}
+		elements[i] += element + "\n"
+	}
+	for _, i := range nested {
+		elements[i] += "},"
+	}
+	return elements
+}
+
+// embeddedType returns the type of the embedded field name, e.g., "BaseModel"
+func (g *Generator) embeddedType(name string) string {
+	for _, field := range g.info.Fields {
+		if field.Embedded && field.Name == name {
+			return field.Type
+		}
+	}
+	return name
+}
+
+// compositeFields formats the elements of a composite literal body, one per
+// line at the given indentation. No elements yield an empty body.
+func compositeFields(elements []string, indent int) string {
+	if len(elements) == 0 {
+		return ""
+	}
+	tabs := strings.Repeat("\t", indent)
+	return "\n" + tabs + "\t" + strings.Join(elements, "\n"+tabs+"\t") + "\n" + tabs
+}
+
+// typeName returns the struct type as referenced in generated code,
+// including type arguments for generic structs, e.g., "Cache[K, V]"
+func (g *Generator) typeName() string {
+	return g.info.Name + g.info.TypeArgs()
+}
+
+// receiverName returns the receiver name of the methods generated on the struct
+func (g *Generator) receiverName() string {
+	return strings.ToLower(string(g.info.Name[0]))
+}
+
 // toLowerCamelCase converts a string to lowerCamelCase
 func toLowerCamelCase(s string) string {
 	if s == "" {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"go/types"
 	"strings"
 	"testing"
 )
@@ -275,3 +276,1453 @@ func TestSkipFieldsInGeneration(t *testing.T) {
 		t.Error("Generated code should not contain skipped field 'internal'")
 	}
 }
+
+func TestGenerateWithNameOverride(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "port", Type: "int", NameOverride: "ListenPort", Pos: "server.go:4"},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Server",
+		ConstructorTypes: []string{"allArgs", "builder", "options"},
+		WithGetter:       true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func NewServer(listenPort int) *Server",
+		"port: listenPort,",
+		"func (b *ServerBuilder) ListenPort(listenPort int) *ServerBuilder",
+		"func WithListenPort(listenPort int) ServerOption",
+		"func (s *Server) GetListenPort() int",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	// Overrides must not clash with other fields
+	info.Fields = append(info.Fields, FieldInfo{Name: "listenPort", Type: "int", Pos: "server.go:5"})
+	if _, err := NewGenerator(config, info).Generate(); err == nil || !strings.Contains(err.Error(), "clashes with field port") {
+		t.Errorf("expected a name clash error, got: %v", err)
+	}
+}
+
+func TestGenerateWithDefaults(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "host", Type: "string", Default: "localhost"},
+			{Name: "timeout", Type: "time.Duration", Default: "30s"},
+			{Name: "workers", Type: "int", Default: "expr:runtime.NumCPU()", SkipSetter: true},
+			{Name: "name", Type: "string"},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Server",
+		ConstructorTypes: []string{"allArgs", "builder", "options"},
+		AllArgsDefaults:  true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		// allArgs replaces zero arguments and sets fields without parameters
+		"if host == \"\" {\n\t\thost = \"localhost\"\n\t}",
+		"if timeout == 0 {\n\t\ttimeout = 30 * time.Second\n\t}",
+		"workers: runtime.NumCPU(),",
+		// builder starts from the defaults
+		"return &ServerBuilder{\n\t\thost:    \"localhost\",\n\t\ttimeout: 30 * time.Second,\n\t}",
+		// options set the defaults before running the options
+		"v := &Server{\n\t\thost:    \"localhost\",\n\t\ttimeout: 30 * time.Second,\n\t\tworkers: runtime.NumCPU(),\n\t}",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "if name ==") {
+		t.Error("Fields without default should not be checked")
+	}
+
+	// Without -allArgsDefaults, arguments are used as given
+	config.AllArgsDefaults = false
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if strings.Contains(code, "if host ==") {
+		t.Error("allArgs should not check arguments without -allArgsDefaults")
+	}
+}
+
+func TestGenerateWithRequiredFields(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Repository",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "dsn", Type: "string", Required: true},
+			{Name: "driver", Type: "Driver", Required: true, NameOverride: "Engine"},
+			{Name: "maxConns", Type: "int"},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Repository",
+		ConstructorTypes: []string{"allArgs", "builder", "options"},
+		ReturnValue:      true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		// allArgs is unchanged
+		"func NewRepository(dsn string, engine Driver, maxConns int) Repository {",
+		// builder tracks the setters of required fields
+		"dsnSet    bool",
+		"b.driverSet = true",
+		"func (b *RepositoryBuilder) Build() (Repository, error) {",
+		"if !b.dsnSet {\n\t\tmissing = append(missing, \"dsn\")",
+		"if !b.driverSet {\n\t\tmissing = append(missing, \"Engine\")",
+		"return Repository{}, fmt.Errorf(\"missing required fields of Repository: %s\", strings.Join(missing, \", \"))",
+		"return v, nil",
+		// options check for zero values
+		"func NewRepositoryWithOptions(opts ...RepositoryOption) (Repository, error) {",
+		"if v.dsn == \"\" {",
+		"if reflect.ValueOf(&v.driver).Elem().IsZero() {",
+		"return *v, nil",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "maxConnsSet") {
+		t.Error("Optional fields should not be tracked")
+	}
+
+	// The flag tracking a required field must not clash with another builder field
+	info.Fields = append(info.Fields, FieldInfo{Name: "dsnSet", Type: "bool", Pos: "repository.go:7"})
+	_, err = NewGenerator(config, info).Generate()
+	if err == nil || !strings.Contains(err.Error(), "builder flag dsnSet of required field dsn clashes with field dsnSet") {
+		t.Errorf("Expected clash error for the dsnSet field, got %v", err)
+	}
+}
+
+func TestGenerateToBuilder(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Repository",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "dsn", Type: "string", Required: true},
+			{Name: "maxConns", Type: "int"},
+			{Name: "connCount", Type: "int", SkipSetter: true, Default: "1"},
+			{Name: "mu", Type: "sync.Mutex", Skip: true},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Repository",
+		ConstructorTypes: []string{"builder"},
+		ToBuilder:        true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"\tdsnSet    bool\n\tconnCount int\n}",
+		"return &RepositoryBuilder{\n\t\tconnCount: 1,\n\t}",
+		"func NewRepositoryBuilderFrom(v *Repository) *RepositoryBuilder {\n\treturn &RepositoryBuilder{\n\t\tdsn:       v.dsn,\n\t\tmaxConns:  v.maxConns,\n\t\tconnCount: v.connCount,\n\t\tdsnSet:    true,\n\t}\n}",
+		"func (r *Repository) ToBuilder() *RepositoryBuilder {\n\treturn NewRepositoryBuilderFrom(r)\n}",
+		"\t\tconnCount: b.connCount,\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "mu:") {
+		t.Error("Skipped fields should not be carried over")
+	}
+
+	if err := validateConfig(&GeneratorConfig{ConstructorTypes: []string{"options"}, ToBuilder: true}); err == nil {
+		t.Error("Expected error for -toBuilder without the builder constructor type")
+	}
+}
+
+func TestGenerateStepBuilder(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Cache",
+		PackageName: "test",
+		TypeParams:  []TypeParam{{Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "any"}},
+		Fields: []FieldInfo{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "items", Type: "map[K]V", Required: true, NameOverride: "Entries"},
+			{Name: "size", Type: "int", Default: "16"},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Cache",
+		ConstructorTypes: []string{"stepBuilder"},
+		SetterPrefix:     "With",
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"type CacheNameStep[K comparable, V any] interface {",
+		"WithName(name string) CacheEntriesStep[K, V]",
+		"WithEntries(entries map[K]V) CacheBuildStep[K, V]",
+		"type CacheBuildStep[K comparable, V any] interface {",
+		"WithSize(size int) CacheBuildStep[K, V]",
+		"Build() *Cache[K, V]",
+		"type cacheStepBuilder[K comparable, V any] struct {",
+		"func NewCacheBuilder[K comparable, V any]() CacheNameStep[K, V] {",
+		"return &cacheStepBuilder[K, V]{\n\t\tsize: 16,\n\t}",
+		"func (b *cacheStepBuilder[K, V]) WithEntries(entries map[K]V) CacheBuildStep[K, V] {",
+		"func (b *cacheStepBuilder[K, V]) Build() *Cache[K, V] {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "missing") {
+		t.Error("Step builder should not check required fields at runtime")
+	}
+
+	// Without required fields the builder starts at the build step
+	info.Fields = info.Fields[2:]
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(code, "func NewCacheBuilder[K comparable, V any]() CacheBuildStep[K, V] {") {
+		t.Error("Step builder without required fields should start at the build step")
+	}
+}
+
+func TestGenerateWithers(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Money",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "amount", Type: "int64"},
+			{Name: "currency", Type: "string", NameOverride: "Unit"},
+			{Name: "rates", Type: "map[string]float64"},
+			{Name: "history", Type: "[]int64", SkipSetter: true},
+			{Name: "cache", Type: "[]byte", Skip: true},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Money",
+		ConstructorTypes: []string{"withers"},
+		ReturnValue:      true,
+		WithersDeepCopy:  true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func (m Money) WithAmount(amount int64) Money {\n\tm.rates = maps.Clone(m.rates)\n\tm.history = slices.Clone(m.history)\n\tm.amount = amount\n\treturn m\n}",
+		"func (m Money) WithUnit(unit string) Money {",
+		"func (m Money) WithRates(rates map[string]float64) Money {\n\tm.history = slices.Clone(m.history)\n\tm.rates = rates\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "WithHistory") || strings.Contains(code, "WithCache") || strings.Contains(code, "m.cache") {
+		t.Error("Withers should respect setter:false and skipped fields")
+	}
+
+	// Pointer receivers copy the struct first
+	config.ReturnValue = false
+	config.WithersDeepCopy = false
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	want := "func (m *Money) WithAmount(amount int64) *Money {\n\tclone := *m\n\tclone.amount = amount\n\treturn &clone\n}"
+	if !strings.Contains(code, want) {
+		t.Errorf("Generated code missing: %s", want)
+	}
+	if strings.Contains(code, "Clone(") {
+		t.Error("Withers should not clone fields without -withersDeepCopy")
+	}
+}
+
+func TestGenerateGetterStyles(t *testing.T) {
+	info := &StructInfo{
+		Name:        "User",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "name", Type: "string"},
+			{Name: "enabled", Type: "bool", GetterName: "IsEnabled"},
+			{Name: "ID", Type: "int", Exported: true},
+		},
+		Methods: []MethodInfo{
+			{Name: "GetName", Results: []string{"string"}, Generated: true},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "User",
+		ConstructorTypes: []string{"allArgs"},
+		WithGetter:       true,
+		GetterStyle:      "plain",
+		Receiver:         "value",
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func (u User) Name() string {\n\treturn u.name\n}",
+		"func (u User) IsEnabled() bool {\n\treturn u.enabled\n}",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	// Methods of generated files are replaced, not reported as clashes
+	config.GetterStyle = ""
+	config.Receiver = ""
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(code, "func (u *User) GetName() string {") {
+		t.Error("Default getters should be named GetX with a pointer receiver")
+	}
+
+	if err := validateConfig(&GeneratorConfig{ConstructorTypes: []string{"allArgs"}, GetterStyle: "java"}); err == nil {
+		t.Error("Expected error for an unknown getter style")
+	}
+	if err := validateConfig(&GeneratorConfig{ConstructorTypes: []string{"allArgs"}, Receiver: "ref"}); err == nil {
+		t.Error("Expected error for an unknown receiver")
+	}
+}
+
+func TestGenerateGetterClashes(t *testing.T) {
+	tests := []struct {
+		name      string
+		fields    []FieldInfo
+		methods   []MethodInfo
+		expectErr string
+	}{
+		{
+			name:      "declared method",
+			fields:    []FieldInfo{{Name: "name", Type: "string"}},
+			methods:   []MethodInfo{{Name: "Name", Results: []string{"string"}}},
+			expectErr: "getter Name of field name clashes with method Name of User",
+		},
+		{
+			name:      "exported field",
+			fields:    []FieldInfo{{Name: "id", Type: "int", GetterName: "Key"}, {Name: "Key", Type: "string", Exported: true}},
+			expectErr: "getter Key of field id clashes with field Key of User",
+		},
+		{
+			name:      "getter override",
+			fields:    []FieldInfo{{Name: "name", Type: "string"}, {Name: "label", Type: "string", GetterName: "Name"}},
+			expectErr: "getter Name of field label clashes with the getter of field name of User",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &StructInfo{Name: "User", PackageName: "test", Fields: tt.fields, Methods: tt.methods}
+			config := &GeneratorConfig{StructName: "User", ConstructorTypes: []string{"allArgs"}, WithGetter: true, GetterStyle: "plain"}
+
+			_, err := NewGenerator(config, info).Generate()
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestGeneratePresence(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Update",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "timeout", Type: "int", Default: "30"},
+			{Name: "set", Type: "uint8", Presence: true, Skip: true},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Update",
+		ConstructorTypes: []string{"builder", "options"},
+		ToBuilder:        true,
+		WithSetter:       true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"updateNamePresent uint8 = 1 << iota",
+		"\tupdateTimeoutPresent\n",
+		"func (u *Update) HasTimeout() bool {\n\treturn u.set&updateTimeoutPresent != 0\n}",
+		"case \"timeout\":\n\t\treturn u.HasTimeout()",
+		// Builder
+		"\tb.timeout = timeout\n\tb.set |= updateTimeoutPresent\n",
+		"\t\tset:     b.set,\n",
+		"set:     v.set,",
+		// Options record presence, so explicit zero values satisfy required fields
+		"\t\ts.timeout = timeout\n\t\ts.set |= updateTimeoutPresent\n",
+		"if v.set&updateNamePresent == 0 {",
+		// Setters
+		"\tu.timeout = timeout\n\tu.set |= updateTimeoutPresent\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "GetSet") || strings.Contains(code, "SetSet") {
+		t.Error("The presence field should have no getter or setter")
+	}
+}
+
+func TestGeneratePresenceErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      []FieldInfo
+		methods     []MethodInfo
+		getterStyle string // Generates getters when set
+		expectErr   string
+	}{
+		{
+			name:      "unsized type",
+			fields:    []FieldInfo{{Name: "port", Type: "int"}, {Name: "set", Type: "uint", Presence: true, Skip: true}},
+			expectErr: "presence field set must be of type uint8, uint16, uint32 or uint64, got uint",
+		},
+		{
+			name: "too many fields",
+			fields: []FieldInfo{
+				{Name: "f1", Type: "int"}, {Name: "f2", Type: "int"}, {Name: "f3", Type: "int"},
+				{Name: "f4", Type: "int"}, {Name: "f5", Type: "int"}, {Name: "f6", Type: "int"},
+				{Name: "f7", Type: "int"}, {Name: "f8", Type: "int"}, {Name: "f9", Type: "int"},
+				{Name: "set", Type: "uint8", Presence: true, Skip: true},
+			},
+			expectErr: "presence field set has 8 bits for 9 fields",
+		},
+		{
+			name:      "exported",
+			fields:    []FieldInfo{{Name: "port", Type: "int"}, {Name: "Set", Type: "uint8", Exported: true, Presence: true, Skip: true}},
+			expectErr: "presence field Set must be unexported",
+		},
+		{
+			name: "two presence fields",
+			fields: []FieldInfo{
+				{Name: "set", Type: "uint8", Presence: true, Skip: true},
+				{Name: "other", Type: "uint8", Presence: true, Skip: true},
+			},
+			expectErr: "field other is the second presence field of Update, after set",
+		},
+		{
+			name:      "method clash",
+			fields:    []FieldInfo{{Name: "port", Type: "int"}, {Name: "set", Type: "uint8", Presence: true, Skip: true}},
+			methods:   []MethodInfo{{Name: "HasPort", Results: []string{"bool"}}},
+			expectErr: "method HasPort of field port clashes with method HasPort of Update",
+		},
+		{
+			name:        "getter clash with IsSet",
+			fields:      []FieldInfo{{Name: "isSet", Type: "bool"}, {Name: "set", Type: "uint8", Presence: true, Skip: true}},
+			getterStyle: getterStylePlain,
+			expectErr:   "method IsSet of presence field set clashes with the getter of field isSet of Update",
+		},
+		{
+			name: "getter clash with Has method",
+			fields: []FieldInfo{
+				{Name: "count", Type: "int"}, {Name: "hasCount", Type: "bool"},
+				{Name: "set", Type: "uint8", Presence: true, Skip: true},
+			},
+			getterStyle: getterStylePlain,
+			expectErr:   "method HasCount of field count clashes with the getter of field hasCount of Update",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &StructInfo{Name: "Update", PackageName: "test", Fields: tt.fields, Methods: tt.methods}
+			config := &GeneratorConfig{StructName: "Update", ConstructorTypes: []string{"builder"}}
+			if tt.getterStyle != "" {
+				config.WithGetter, config.GetterStyle = true, tt.getterStyle
+			}
+
+			_, err := NewGenerator(config, info).Generate()
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestGenerateCollectionHelpers(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Client",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "headers", Type: "map[string]string"},
+			{Name: "plugins", Type: "[]string"},
+			{Name: "aliases", Type: "[]string", ItemName: "alias"},
+			{Name: "data", Type: "[]byte"},
+			{Name: "set", Type: "uint8", Presence: true, Skip: true},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:        "Client",
+		ConstructorTypes:  []string{"builder", "options"},
+		OptionStyle:       "interface",
+		CollectionHelpers: true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func (b *ClientBuilder) PutHeader(key string, value string) *ClientBuilder {\n\tif b.headers == nil {\n\t\tb.headers = make(map[string]string)\n\t}\n\tb.headers[key] = value\n\tb.set |= clientHeadersPresent\n",
+		"func (b *ClientBuilder) AddPlugin(plugin string) *ClientBuilder {\n\tb.plugins = append(b.plugins, plugin)\n",
+		"func (b *ClientBuilder) AddPlugins(plugins ...string) *ClientBuilder {\n\tb.plugins = append(b.plugins, plugins...)\n",
+		"func (b *ClientBuilder) AddAlias(alias string) *ClientBuilder {",
+		"func WithPlugin(plugin string) ClientOption {\n\treturn ClientOptionFunc(func(s *Client) {\n\t\ts.plugins = append(s.plugins, plugin)\n",
+		"func WithHeader(key string, value string) ClientOption {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "AddDatum") || strings.Contains(code, "AddData(") {
+		t.Error("Fields without a plural name should not get collection helpers")
+	}
+
+	// Adding items sets required fields, and element types keep their struct tags
+	required := &StructInfo{
+		Name:        "Req",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "tags", Type: "[]string", Required: true},
+			{Name: "labels", Type: "map[string]string", Required: true},
+			{Name: "items", Type: "[]struct {\n\tName string `json:\"name\"`\n}"},
+		},
+	}
+	code, err = NewGenerator(&GeneratorConfig{ConstructorTypes: []string{"builder"}, CollectionHelpers: true}, required).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, exp := range []string{
+		"\tb.tags = append(b.tags, tag)\n\tb.tagsSet = true\n\treturn b\n",
+		"\tb.tags = append(b.tags, tags...)\n\tb.tagsSet = true\n\treturn b\n",
+		"\tb.labels[key] = value\n\tb.labelsSet = true\n\treturn b\n",
+		"func (b *ReqBuilder) AddItem(item struct {\n\tName string `json:\"name\"`\n}) *ReqBuilder {",
+	} {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	// Builders created from an instance copy the collections the helpers write to
+	code, err = NewGenerator(&GeneratorConfig{ConstructorTypes: []string{"builder"}, CollectionHelpers: true, ToBuilder: true}, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, exp := range []string{
+		"\t\theaders: maps.Clone(v.headers),\n",
+		"\t\tplugins: slices.Clone(v.plugins),\n",
+		"\t\tdata:    v.data,\n",
+	} {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	// Item options follow the option style and naming
+	config.OptionStyle = "error"
+	config.OptionNaming = "namespace"
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(code, "func (clientOptions) Plugin(plugin string) ClientOption {\n\treturn func(s *Client) error {") {
+		t.Error("Item options should be methods of the namespace returning errors")
+	}
+}
+
+func TestGenerateCollectionHelpersErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		fields    []FieldInfo
+		expectErr string
+	}{
+		{
+			name:      "item of a non-collection",
+			fields:    []FieldInfo{{Name: "port", Type: "int", ItemName: "entry"}},
+			expectErr: "field port has an item name but is not of a slice or map type",
+		},
+		{
+			name:      "item clashing with a field",
+			fields:    []FieldInfo{{Name: "tag", Type: "string"}, {Name: "tags", Type: "[]string"}},
+			expectErr: "item tag of field tags clashes with field tag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &StructInfo{Name: "Client", PackageName: "test", Fields: tt.fields}
+			config := &GeneratorConfig{StructName: "Client", ConstructorTypes: []string{"builder"}, CollectionHelpers: true}
+
+			_, err := NewGenerator(config, info).Generate()
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestSingularName(t *testing.T) {
+	tests := map[string]string{
+		"tags":      "tag",
+		"entries":   "entry",
+		"addresses": "address",
+		"boxes":     "box",
+		"matches":   "match",
+		"headers":   "header",
+		"data":      "",
+		"class":     "",
+		"s":         "",
+	}
+	for name, expect := range tests {
+		if got := singularName(name); got != expect {
+			t.Errorf("singularName(%q) = %q, want %q", name, got, expect)
+		}
+	}
+}
+
+func TestGenerateNestedBuilders(t *testing.T) {
+	server := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "tls", Type: "*TLSConfig", Required: true},
+			{Name: "pool", Type: "Pool"},
+			{Name: "limits", Type: "*Limits"},
+			{Name: "store", Type: "Store"},
+		},
+		// Builders found in the package; the one of Store is generated again below
+		Builders: map[string]BuilderInfo{
+			"Limits": {ReturnsValue: true},
+			"Store":  {},
+		},
+	}
+	tlsConfig := &StructInfo{
+		Name:        "TLSConfig",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "certFile", Type: "string"}},
+	}
+	pool := &StructInfo{
+		Name:        "Pool",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "size", Type: "int"}},
+	}
+	store := &StructInfo{
+		Name:        "Store",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "dsn", Type: "string", Required: true}},
+	}
+
+	config := &GeneratorConfig{ConstructorTypes: []string{"builder"}, NestedBuilders: true}
+	code, err := GenerateFile([]*Generator{
+		NewGenerator(config, server),
+		NewGenerator(config, tlsConfig),
+		NewGenerator(config, pool),
+		NewGenerator(config, store),
+	})
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	expected := []string{
+		"func (b *ServerBuilder) ConfigureTls(configure func(*TLSConfigBuilder)) *ServerBuilder {\n\tnested := NewTLSConfigBuilder()\n\tconfigure(nested)\n\tb.tls = nested.Build()\n\tb.tlsSet = true\n\treturn b\n}",
+		"\tb.pool = *nested.Build()\n",
+		"\tv := nested.Build()\n\tb.limits = &v\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	// The Build method of Store now returns an error
+	if strings.Contains(code, "ConfigureStore") {
+		t.Error("Builders returning an error should not be nested")
+	}
+
+	if err := validateConfig(&GeneratorConfig{ConstructorTypes: []string{"options"}, NestedBuilders: true}); err == nil {
+		t.Error("Expected error for -nestedBuilders without a builder")
+	}
+}
+
+func TestGenerateFlatten(t *testing.T) {
+	info := &StructInfo{
+		Name:        "User",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "BaseModel", Type: "BaseModel", Exported: true, Embedded: true, Flatten: true, Skip: true},
+			{Name: "ID", Type: "int64", Exported: true, Parent: "BaseModel"},
+			{Name: "version", Type: "int", Parent: "BaseModel", Default: "1", SkipSetter: true},
+			{Name: "name", Type: "string", Required: true},
+		},
+	}
+
+	config := &GeneratorConfig{ConstructorTypes: []string{"allArgs", "builder", "options"}, WithGetter: true}
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func NewUser(iD int64, name string) *User {",
+		"	return &User{\n\t\tBaseModel: BaseModel{\n\t\t\tID:      iD,\n\t\t\tversion: 1,\n\t\t},\n\t\tname: name,\n\t}",
+		"func (b *UserBuilder) ID(iD int64) *UserBuilder {",
+		"\tv := &User{\n\t\tBaseModel: BaseModel{\n\t\t\tID:      b.iD,\n\t\t\tversion: 1,\n\t\t},\n\t\tname: b.name,\n\t}",
+		"\tv := &User{\n\t\tBaseModel: BaseModel{\n\t\t\tversion: 1,\n\t\t},\n\t}",
+		"\t\ts.BaseModel.ID = iD\n",
+		"func (u *User) GetVersion() int {\n\treturn u.BaseModel.version\n}",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "baseModel") {
+		t.Error("The flattened embedded field should not be set as a whole")
+	}
+}
+
+func TestGenerateFieldDocs(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "host", Type: "string", Doc: "Host name to bind to.\n\nEmpty binds all interfaces."},
+			{Name: "port", Type: "int", Doc: "TCP port to listen on"},
+			{Name: "timeout", Type: "int"},
+		},
+	}
+
+	config := &GeneratorConfig{ConstructorTypes: []string{"allArgs", "builder", "options"}, WithGetter: true, WithSetter: true}
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"// NewServer creates a new Server\n//\n// Parameters:\n//   - host: Host name to bind to.\n//     Empty binds all interfaces.\n//   - port: TCP port to listen on\nfunc NewServer(",
+		"// Host sets the host field\n//\n// Host name to bind to.\n//\n// Empty binds all interfaces.\nfunc (b *ServerBuilder) Host(",
+		"// WithPort sets the port field\n//\n// TCP port to listen on\nfunc WithPort(",
+		"// GetPort returns the port field\n//\n// TCP port to listen on\nfunc (s *Server) GetPort(",
+		"// SetPort sets the port field\n//\n// TCP port to listen on\nfunc (s *Server) SetPort(",
+		// Undocumented fields keep the generated comment only
+		"// WithTimeout sets the timeout field\nfunc WithTimeout(",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+}
+
+func TestGenerateStructDoc(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Doc:         "Server serves HTTP requests.\n\nIt is safe for concurrent use.",
+		Fields: []FieldInfo{
+			{Name: "host", Type: "string", Doc: "Host name to bind to"},
+		},
+	}
+
+	config := &GeneratorConfig{ConstructorTypes: []string{"allArgs", "builder", "options"}}
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	doc := "//\n// Server serves HTTP requests.\n//\n// It is safe for concurrent use.\n"
+	expected := []string{
+		"// NewServer creates a new Server\n" + doc + "//\n// Parameters:\n//   - host: Host name to bind to\nfunc NewServer(",
+		"// ServerBuilder is a builder for Server\n" + doc + "type ServerBuilder struct {",
+		"// ServerOption is a functional option for configuring Server\n" + doc + "type ServerOption func(*Server)",
+		"// NewServerWithOptions creates a new Server with functional options\n" + doc + "func NewServerWithOptions(",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	config = &GeneratorConfig{ConstructorTypes: []string{"options"}, OptionStyle: "interface"}
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(code, "// ServerOption configures a Server\n"+doc+"type ServerOption interface {") {
+		t.Error("Option interface should carry the struct doc")
+	}
+}
+
+func TestGenerateDeprecated(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Client",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "timeout", Type: "int", Doc: "Request timeout"},
+			{Name: "retries", Type: "int", Default: "3", Doc: "Retry count.\n\nDeprecated: use policy instead.", Deprecated: "use policy instead."},
+			{Name: "hosts", Type: "[]string", Doc: "Deprecated: use policy instead.", Deprecated: "use policy instead."},
+		},
+	}
+
+	config := &GeneratorConfig{ConstructorTypes: []string{"allArgs", "builder", "options"}, WithGetter: true, CollectionHelpers: true, OmitDeprecated: true}
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		// Deprecated fields are left out of the allArgs constructor, starting from their default
+		"// NewClient creates a new Client\n//\n// Parameters:\n//   - timeout: Request timeout\nfunc NewClient(timeout int) *Client {",
+		"\t\tretries: 3,\n",
+		"// WithRetries sets the retries field\n//\n// Retry count.\n//\n// Deprecated: use policy instead.\nfunc WithRetries(",
+		"// GetRetries returns the retries field\n//\n// Retry count.\n//\n// Deprecated: use policy instead.\nfunc (c *Client) GetRetries(",
+		"// AddHost appends host to the hosts field\n//\n// Deprecated: use policy instead.\nfunc (b *ClientBuilder) AddHost(",
+		"// WithHost appends host to the hosts field\n//\n// Deprecated: use policy instead.\nfunc WithHost(",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	// Without -omitDeprecated, the parameter docs mark deprecated fields without deprecating the constructor
+	config = &GeneratorConfig{ConstructorTypes: []string{"allArgs"}}
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(code, "//   - retries: Retry count.\n//     (deprecated: use policy instead.)\n") {
+		t.Errorf("Expected the retries parameter to be marked as deprecated, got:\n%s", code)
+	}
+	if !strings.Contains(code, "func NewClient(timeout int, retries int, hosts []string) *Client {") {
+		t.Errorf("Expected deprecated fields to be parameters without -omitDeprecated, got:\n%s", code)
+	}
+
+	if err := validateConfig(&GeneratorConfig{ConstructorTypes: []string{"builder"}, OmitDeprecated: true}); err == nil {
+		t.Error("Expected error for -omitDeprecated without the allArgs constructor")
+	}
+}
+
+func TestGenerateSetters(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Account",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "owner", Type: "string", Required: true},
+			{Name: "limit", Type: "int", Options: []TagOption{{Key: "min", Value: "0"}}},
+			{Name: "note", Type: "string", NameOverride: "Comment"},
+			{Name: "balance", Type: "int", SkipSetter: true},
+			{Name: "ID", Type: "string", Exported: true},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Account",
+		ConstructorTypes: []string{"allArgs"},
+		WithSetter:       true,
+		FluentSetter:     true,
+		SetterChecks:     true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func (a *Account) SetOwner(owner string) error {\n\tif owner == \"\" {\n\t\treturn errors.New(\"missing required fields of Account: owner\")\n\t}\n\ta.owner = owner\n\treturn nil\n}",
+		"func (a *Account) SetLimit(limit int) error {\n\tif limit < 0 {\n\t\treturn &AccountValidationError{Field: \"limit\", Rule: \"min=0\"}\n\t}\n",
+		"func (a *Account) SetComment(comment string) *Account {\n\ta.note = comment\n\treturn a\n}",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if strings.Contains(code, "SetBalance") || strings.Contains(code, "SetID") {
+		t.Error("Setters should skip setter:false and exported fields")
+	}
+
+	// Plain setters return nothing and check nothing
+	config.FluentSetter = false
+	config.SetterChecks = false
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	want := "func (a *Account) SetOwner(owner string) {\n\ta.owner = owner\n}"
+	if !strings.Contains(code, want) {
+		t.Errorf("Generated code missing: %s", want)
+	}
+}
+
+func TestGenerateInterfaceOptions(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Cache",
+		PackageName: "test",
+		TypeParams:  []TypeParam{{Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "any"}},
+		Fields: []FieldInfo{
+			{Name: "size", Type: "int", Default: "16"},
+			{Name: "items", Type: "map[K]V", NameOverride: "Entries"},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Cache",
+		ConstructorTypes: []string{"options"},
+		OptionStyle:      "interface",
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"type CacheOption[K comparable, V any] interface {\n\tapply(*Cache[K, V])\n}",
+		"type CacheOptionFunc[K comparable, V any] func(*Cache[K, V])",
+		"func (f CacheOptionFunc[K, V]) apply(s *Cache[K, V]) {\n\tf(s)\n}",
+		"type cacheSizeOption[K comparable, V any] struct {\n\tsize int\n}",
+		"func (o cacheSizeOption[K, V]) apply(s *Cache[K, V]) {\n\ts.size = o.size\n}",
+		"func (o cacheEntriesOption[K, V]) String() string {\n\treturn fmt.Sprintf(\"WithEntries(%v)\", o.entries)\n}",
+		"func WithEntries[K comparable, V any](entries map[K]V) CacheOption[K, V] {\n\treturn cacheEntriesOption[K, V]{entries: entries}\n}",
+		"opt.apply(v)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	if err := validateConfig(&GeneratorConfig{ConstructorTypes: []string{"options"}, OptionStyle: "struct"}); err == nil {
+		t.Error("Expected error for an unknown option style")
+	}
+}
+
+func TestGenerateErrorOptions(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "host", Type: "string"},
+			{Name: "port", Type: "int", Options: []TagOption{{Key: "min", Value: "1"}}},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Server",
+		ConstructorTypes: []string{"options"},
+		OptionStyle:      "error",
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"type ServerOption func(*Server) error",
+		"func WithHost(host string) ServerOption {\n\treturn func(s *Server) error {\n\t\ts.host = host\n\t\treturn nil\n\t}\n}",
+		"// WithPort sets the port field, failing if the value violates the rules of its tag",
+		"\treturn func(s *Server) error {\n\t\tif port < 1 {\n\t\t\treturn &ServerValidationError{Field: \"port\", Rule: \"min=1\"}\n\t\t}\n\t\ts.port = port\n",
+		"// returning an error if an option fails or a field violates the rules of its tag",
+		"func NewServerWithOptions(opts ...ServerOption) (*Server, error) {",
+		"\t\tif err := opt(v); err != nil {\n\t\t\terrs = append(errs, err)\n\t\t}",
+		"\tif err := errors.Join(errs...); err != nil {\n\t\treturn nil, err\n\t}",
+		"\treturn v, nil\n}",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	// Without rules or hooks, only the options can fail
+	info.Fields = info.Fields[:1]
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(code, "// returning an error if an option fails\nfunc NewServerWithOptions(opts ...ServerOption) (*Server, error) {") {
+		t.Error("Options returning errors should make the constructor return an error")
+	}
+}
+
+func TestGenerateOptionNaming(t *testing.T) {
+	server := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "name", Type: "string"}, {Name: "port", Type: "int"}},
+	}
+	client := &StructInfo{
+		Name:        "Client",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "name", Type: "string"}},
+	}
+	options := []string{"options"}
+
+	tests := []struct {
+		name     string
+		server   string
+		client   string
+		expected []string
+	}{
+		{
+			name:     "prefixed",
+			server:   "prefixed",
+			client:   "prefixed",
+			expected: []string{"func WithServerName(name string) ServerOption {", "func WithClientName(name string) ClientOption {"},
+		},
+		{
+			name:   "namespace",
+			server: "namespace",
+			client: "field",
+			expected: []string{
+				"type serverOptions struct{}",
+				"var ServerOpts serverOptions",
+				"func (serverOptions) Port(port int) ServerOption {",
+				"func WithName(name string) ClientOption {",
+			},
+		},
+		{
+			name:     "auto",
+			server:   "auto",
+			client:   "field",
+			expected: []string{"func WithServerName(name string) ServerOption {", "func WithServerPort(port int) ServerOption {", "func WithName(name string) ClientOption {"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generators := []*Generator{
+				NewGenerator(&GeneratorConfig{StructName: "Server", ConstructorTypes: options, OptionNaming: tt.server}, server),
+				NewGenerator(&GeneratorConfig{StructName: "Client", ConstructorTypes: options, OptionNaming: tt.client}, client),
+			}
+			code, err := GenerateFile(generators)
+			if err != nil {
+				t.Fatalf("GenerateFile failed: %v", err)
+			}
+			for _, exp := range tt.expected {
+				if !strings.Contains(code, exp) {
+					t.Errorf("Generated code missing: %s", exp)
+				}
+			}
+		})
+	}
+
+	// Auto naming keeps field names when nothing clashes
+	code, err := NewGenerator(&GeneratorConfig{StructName: "Server", ConstructorTypes: options, OptionNaming: "auto"}, server).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(code, "func WithName(name string) ServerOption {") {
+		t.Error("Auto naming without clashes should use field names")
+	}
+}
+
+func TestGenerateOptionNamingErrors(t *testing.T) {
+	server := &StructInfo{Name: "Server", PackageName: "test", Fields: []FieldInfo{{Name: "name", Type: "string"}}}
+	client := &StructInfo{Name: "Client", PackageName: "test", Fields: []FieldInfo{{Name: "name", Type: "string"}}}
+	generators := []*Generator{
+		NewGenerator(&GeneratorConfig{StructName: "Server", ConstructorTypes: []string{"options"}}, server),
+		NewGenerator(&GeneratorConfig{StructName: "Client", ConstructorTypes: []string{"options"}}, client),
+	}
+
+	_, err := GenerateFile(generators)
+	if err == nil || !strings.Contains(err.Error(), "option function WithName is generated for both Server and Client") {
+		t.Errorf("Expected option function clash error, got %v", err)
+	}
+
+	cache := &StructInfo{
+		Name:        "Cache",
+		PackageName: "test",
+		TypeParams:  []TypeParam{{Name: "K", Constraint: "comparable"}},
+		Fields:      []FieldInfo{{Name: "keys", Type: "[]K"}},
+	}
+	config := &GeneratorConfig{StructName: "Cache", ConstructorTypes: []string{"options"}, OptionNaming: "namespace"}
+	if _, err := NewGenerator(config, cache).Generate(); err == nil {
+		t.Error("Expected error for namespace naming of a generic struct")
+	}
+}
+
+func TestGenerateStepBuilderErrors(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Job",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "build", Type: "string", Required: true}},
+	}
+	config := &GeneratorConfig{StructName: "Job", ConstructorTypes: []string{"stepBuilder"}}
+
+	if _, err := NewGenerator(config, info).Generate(); err == nil {
+		t.Error("Expected error when a required field clashes with the build step")
+	}
+
+	if err := validateConfig(&GeneratorConfig{ConstructorTypes: []string{"builder", "stepBuilder"}}); err == nil {
+		t.Error("Expected error when combining builder and stepBuilder")
+	}
+}
+
+func TestGenerateWithValidateAndContextInit(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Endpoint",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "host", Type: "string", Required: true},
+		},
+		Methods: []MethodInfo{
+			{Name: "resolve", Params: []string{"context.Context"}, Results: []string{"error"}},
+			{Name: "Validate", Results: []string{"error"}},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Endpoint",
+		ConstructorTypes: []string{"allArgs", "builder", "options"},
+		InitFunc:         "resolve",
+		ReturnValue:      true,
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"// NewEndpoint creates a new Endpoint, returning an error if resolve or Validate fails",
+		"func NewEndpoint(ctx context.Context, host string) (Endpoint, error) {",
+		"if err := v.resolve(ctx); err != nil {\n\t\treturn Endpoint{}, err\n\t}",
+		"if err := v.Validate(); err != nil {\n\t\treturn Endpoint{}, err\n\t}",
+		"// Build builds the Endpoint, returning an error if a required field was not set or resolve or Validate fails",
+		"func (b *EndpointBuilder) Build(ctx context.Context) (Endpoint, error) {",
+		"func NewEndpointWithOptions(ctx context.Context, opts ...EndpointOption) (Endpoint, error) {",
+		"return *v, nil",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+
+	// Disabling detection keeps the plain signatures
+	info.Fields[0].Required = false
+	config.InitFunc = ""
+	config.ValidateFunc = "-"
+	code, err = NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if strings.Contains(code, "Validate") || !strings.Contains(code, "func NewEndpoint(host string) Endpoint {") {
+		t.Errorf("Validate should not be called with -validate=-:\n%s", code)
+	}
+}
+
+func TestGenerateWithFieldRules(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "env", Type: "Env", Options: []TagOption{{Key: "oneof", Value: "dev|prod"}}, TypeInfo: types.Typ[types.String]},
+			{Name: "port", Type: "int", Options: []TagOption{{Key: "min", Value: "1"}, {Key: "max", Value: "65535"}}, NameOverride: "ListenPort"},
+			{Name: "name", Type: "string", Options: []TagOption{{Key: "match", Value: "^[a-z]+$"}}},
+			{Name: "internal", Type: "string", Skip: true},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Server",
+		ConstructorTypes: []string{"allArgs", "builder", "options"},
+	}
+
+	code, err := NewGenerator(config, info).Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"type ServerValidationError struct {",
+		"func (e *ServerValidationError) Error() string {",
+		"var serverNamePattern = regexp.MustCompile(`^[a-z]+$`)",
+		"func validateServerFields(v *Server) error {",
+		"if v.env != \"dev\" && v.env != \"prod\" {\n\t\treturn &ServerValidationError{Field: \"env\", Rule: \"oneof=dev|prod\"}",
+		"if v.port > 65535 {\n\t\treturn &ServerValidationError{Field: \"ListenPort\", Rule: \"max=65535\"}",
+		"if !serverNamePattern.MatchString(v.name) {",
+		// Every constructor checks the rules
+		"func NewServer(env Env, listenPort int, name string) (*Server, error) {",
+		"func (b *ServerBuilder) Build() (*Server, error) {",
+		"func NewServerWithOptions(opts ...ServerOption) (*Server, error) {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(code, exp) {
+			t.Errorf("Generated code missing: %s", exp)
+		}
+	}
+	if n := strings.Count(code, "if err := validateServerFields(v); err != nil {"); n != 3 {
+		t.Errorf("Expected 3 calls of validateServerFields, got %d", n)
+	}
+
+	// Rules are checked against the field type
+	info.Fields[2].Type = "int"
+	if _, err := NewGenerator(config, info).Generate(); err == nil || !strings.Contains(err.Error(), "does not apply to type int") {
+		t.Errorf("expected a rule type error, got: %v", err)
+	}
+}
+
+func TestGenerateFieldRulesWithReturnValue(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Port",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "port", Type: "int", Options: []TagOption{{Key: "min", Value: "1"}}},
+		},
+	}
+
+	// allArgs and the Build methods declare v as a value, options as a pointer
+	tests := []struct {
+		constructorType string
+		call            string
+	}{
+		{"allArgs", "if err := validatePortFields(&v); err != nil {"},
+		{"builder", "if err := validatePortFields(&v); err != nil {"},
+		{"stepBuilder", "if err := validatePortFields(&v); err != nil {"},
+		{"options", "if err := validatePortFields(v); err != nil {"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constructorType, func(t *testing.T) {
+			config := &GeneratorConfig{
+				StructName:       "Port",
+				ConstructorTypes: []string{tt.constructorType},
+				ReturnValue:      true,
+			}
+
+			code, err := NewGenerator(config, info).Generate()
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if n := strings.Count(code, "validatePortFields("); n != 2 {
+				t.Errorf("Expected the rule function and 1 call, got %d occurrences", n)
+			}
+			if !strings.Contains(code, tt.call) {
+				t.Errorf("Generated code missing: %s", tt.call)
+			}
+		})
+	}
+}
+
+func TestGenerateGenericStruct(t *testing.T) {
+	info := &StructInfo{
+		Name:        "Cache",
+		PackageName: "test",
+		TypeParams: []TypeParam{
+			{Name: "K", Constraint: "comparable"},
+			{Name: "V", Constraint: "any"},
+		},
+		Fields: []FieldInfo{
+			{Name: "items", Type: "map[K]V"},
+			{Name: "size", Type: "int"},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "Cache",
+		ConstructorTypes: []string{"allArgs", "builder", "options"},
+		WithGetter:       true,
+	}
+
+	gen := NewGenerator(config, info)
+	code, err := gen.Generate()
+
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func NewCache[K comparable, V any](items map[K]V, size int) *Cache[K, V] {",
+		"return &Cache[K, V]{",
+		"type CacheBuilder[K comparable, V any] struct {",
+		"func NewCacheBuilder[K comparable, V any]() *CacheBuilder[K, V] {",
+		"func (b *CacheBuilder[K, V]) Items(items map[K]V) *CacheBuilder[K, V] {",
+		"func (b *CacheBuilder[K, V]) Build() *Cache[K, V] {",
+		"type CacheOption[K comparable, V any] func(*Cache[K, V])",
+		"func WithItems[K comparable, V any](items map[K]V) CacheOption[K, V] {",
+		"func NewCacheWithOptions[K comparable, V any](opts ...CacheOption[K, V]) *Cache[K, V] {",
+		"func (c *Cache[K, V]) GetItems() map[K]V {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(code, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+}
+
+func TestGenerateWithFuncTypedFields(t *testing.T) {
+	info := &StructInfo{
+		Name:        "TestStruct",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "handlers", Type: "map[string]func(ctx context.Context) error"},
+			{Name: "onClose", Type: "func()"},
+		},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "TestStruct",
+		ConstructorTypes: []string{"allArgs", "builder", "options"},
+	}
+
+	gen := NewGenerator(config, info)
+	code, err := gen.Generate()
+
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		"func NewTestStruct(handlers map[string]func(ctx context.Context) error, onClose func()) *TestStruct {",
+		"func (b *TestStructBuilder) OnClose(onClose func()) *TestStructBuilder {",
+		"func WithHandlers(handlers map[string]func(ctx context.Context) error) TestStructOption {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(code, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+}
+
+func TestGenerateWithImports(t *testing.T) {
+	info := &StructInfo{
+		Name:        "TestStruct",
+		PackageName: "test",
+		Fields: []FieldInfo{
+			{Name: "timeout", Type: "tm.Duration"},
+		},
+		Imports: []ImportInfo{{Name: "tm", Path: "time"}},
+	}
+
+	config := &GeneratorConfig{
+		StructName:       "TestStruct",
+		ConstructorTypes: []string{"allArgs"},
+	}
+
+	gen := NewGenerator(config, info)
+	code, err := gen.Generate()
+
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if !strings.Contains(code, `tm "time"`) {
+		t.Error("Generated code should import time with its alias")
+	}
+}
+
+func TestGenerateFileMultipleStructs(t *testing.T) {
+	server := &StructInfo{
+		Name:        "Server",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "host", Type: "string"}},
+	}
+	client := &StructInfo{
+		Name:        "Client",
+		PackageName: "test",
+		Fields:      []FieldInfo{{Name: "timeout", Type: "int"}},
+	}
+
+	generators := []*Generator{
+		NewGenerator(&GeneratorConfig{StructName: "Server", ConstructorTypes: []string{"allArgs"}}, server),
+		NewGenerator(&GeneratorConfig{StructName: "Client", ConstructorTypes: []string{"builder"}}, client),
+	}
+
+	code, err := GenerateFile(generators)
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	if strings.Count(code, "package test") != 1 {
+		t.Error("Generated file should contain a single package clause")
+	}
+	if strings.Count(code, "Code generated by constructor. DO NOT EDIT.") != 1 {
+		t.Error("Generated file should contain a single generated-code header")
+	}
+
+	serverIdx := strings.Index(code, "func NewServer(")
+	clientIdx := strings.Index(code, "func NewClientBuilder(")
+	if serverIdx < 0 || clientIdx < 0 {
+		t.Fatal("Generated file should contain code for both structs")
+	}
+	if serverIdx > clientIdx {
+		t.Error("Generated code should follow the order of the generators")
+	}
+}
+
+func TestGenerateFilePackageMismatch(t *testing.T) {
+	generators := []*Generator{
+		NewGenerator(&GeneratorConfig{ConstructorTypes: []string{"allArgs"}}, &StructInfo{Name: "A", PackageName: "one"}),
+		NewGenerator(&GeneratorConfig{ConstructorTypes: []string{"allArgs"}}, &StructInfo{Name: "B", PackageName: "two"}),
+	}
+
+	if _, err := GenerateFile(generators); err == nil {
+		t.Error("Expected error when structs belong to different packages")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+)
+
+// defaultValidateFunc is the validation method used when the struct declares
+// it with the signature func() error and -validate is not given
+const defaultValidateFunc = "Validate"
+
+// constructorHooks describes the methods generated constructors call on the
+// new struct before returning it
+type constructorHooks struct {
+	ruleFunc     string // Generated function checking the field rules, empty if no field has rules
+	initFunc     string // Initialization method, empty if none
+	initContext  bool   // Whether the initialization method takes a context.Context
+	initError    bool   // Whether the initialization method returns an error
+	validateFunc string // Validation method returning an error, empty if none
+}
+
+// resolveHooks determines the initialization and validation methods to call,
+// checking their signatures against the methods declared on the struct.
+// Methods the parser does not know about, e.g., promoted from an embedded
+// field without -typed, are assumed to have the plain signatures func() for
+// -init and func() error for -validate.
+func (g *Generator) resolveHooks() (constructorHooks, error) {
+	var hooks constructorHooks
+
+	if g.hasFieldRules() {
+		hooks.ruleFunc = g.ruleFuncName()
+	}
+
+	if name := g.config.InitFunc; name != "" {
+		hooks.initFunc = name
+		if method, ok := g.info.Method(name); ok {
+			switch {
+			case len(method.Params) == 0:
+			case len(method.Params) == 1 && method.Params[0] == "context.Context":
+				hooks.initContext = true
+			default:
+				return hooks, fmt.Errorf("init method %s must take no arguments or a single context.Context", name)
+			}
+			switch {
+			case len(method.Results) == 0:
+			case slices.Equal(method.Results, []string{"error"}):
+				hooks.initError = true
+			default:
+				return hooks, fmt.Errorf("init method %s must return nothing or an error", name)
+			}
+		}
+	}
+
+	switch name := g.config.ValidateFunc; name {
+	case "-":
+		// Detection disabled
+	case "":
+		if method, ok := g.info.Method(defaultValidateFunc); ok && isValidateSignature(method) {
+			hooks.validateFunc = defaultValidateFunc
+		}
+	default:
+		if method, ok := g.info.Method(name); ok && !isValidateSignature(method) {
+			return hooks, fmt.Errorf("validate method %s must have the signature func() error", name)
+		}
+		hooks.validateFunc = name
+	}
+
+	return hooks, nil
+}
+
+// isValidateSignature reports whether method has the signature func() error
+func isValidateSignature(method MethodInfo) bool {
+	return len(method.Params) == 0 && slices.Equal(method.Results, []string{"error"})
+}
+
+// returnsError reports whether constructors calling the hooks return an error
+func (h constructorHooks) returnsError() bool {
+	return h.ruleFunc != "" || h.initError || h.validateFunc != ""
+}
+
+// calls generates the statements calling the hooks on v: the field rules are
+// checked first, then the init and validate methods are called. ptr is the
+// expression passing v to the rule function as a pointer, i.e., "&v" when v
+// is declared as a value. Errors are returned together with zeroResult.
+func (h constructorHooks) calls(ptr, zeroResult string) string {
+	var buf bytes.Buffer
+
+	if h.ruleFunc != "" {
+		buf.WriteString(fmt.Sprintf("\tif err := %s(%s); err != nil {\n\t\treturn %s, err\n\t}\n", h.ruleFunc, ptr, zeroResult))
+	}
+
+	if h.initFunc != "" {
+		args := ""
+		if h.initContext {
+			args = "ctx"
+		}
+		call := fmt.Sprintf("v.%s(%s)", h.initFunc, args)
+		if h.initError {
+			buf.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n\t\treturn %s, err\n\t}\n", call, zeroResult))
+		} else {
+			buf.WriteString(fmt.Sprintf("\t%s\n", call))
+		}
+	}
+
+	if h.validateFunc != "" {
+		buf.WriteString(fmt.Sprintf("\tif err := v.%s(); err != nil {\n\t\treturn %s, err\n\t}\n", h.validateFunc, zeroResult))
+	}
+
+	return buf.String()
+}
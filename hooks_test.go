@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveHooks(t *testing.T) {
+	methods := []MethodInfo{
+		{Name: "setup"},
+		{Name: "open", Params: []string{"context.Context"}, Results: []string{"error"}},
+		{Name: "Validate", Results: []string{"error"}},
+		{Name: "Check", Results: []string{"error"}},
+		{Name: "load", Params: []string{"string"}},
+		{Name: "count", Results: []string{"int"}},
+	}
+
+	tests := []struct {
+		name     string
+		init     string
+		validate string
+		expect   constructorHooks
+	}{
+		{"no hooks", "", "-", constructorHooks{}},
+		{"detected validate", "", "", constructorHooks{validateFunc: "Validate"}},
+		{"plain init", "setup", "-", constructorHooks{initFunc: "setup"}},
+		{"context init", "open", "-", constructorHooks{initFunc: "open", initContext: true, initError: true}},
+		{"unknown init", "promoted", "-", constructorHooks{initFunc: "promoted"}},
+		{"explicit validate", "setup", "Check", constructorHooks{initFunc: "setup", validateFunc: "Check"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(&GeneratorConfig{InitFunc: tt.init, ValidateFunc: tt.validate}, &StructInfo{Methods: methods})
+			hooks, err := g.resolveHooks()
+			if err != nil {
+				t.Fatalf("resolveHooks failed: %v", err)
+			}
+			if hooks != tt.expect {
+				t.Errorf("resolveHooks() = %+v, want %+v", hooks, tt.expect)
+			}
+		})
+	}
+
+	// Validate is not detected with another signature
+	g := NewGenerator(&GeneratorConfig{}, &StructInfo{Methods: []MethodInfo{{Name: "Validate", Results: []string{"bool"}}}})
+	if hooks, err := g.resolveHooks(); err != nil || hooks.validateFunc != "" {
+		t.Errorf("resolveHooks() = (%+v, %v), want no validate method", hooks, err)
+	}
+}
+
+func TestResolveHooksErrors(t *testing.T) {
+	methods := []MethodInfo{
+		{Name: "load", Params: []string{"string"}},
+		{Name: "count", Results: []string{"int"}},
+	}
+
+	tests := []struct {
+		name      string
+		config    GeneratorConfig
+		expectErr string
+	}{
+		{"init arguments", GeneratorConfig{InitFunc: "load"}, "must take no arguments or a single context.Context"},
+		{"init results", GeneratorConfig{InitFunc: "count"}, "must return nothing or an error"},
+		{"validate signature", GeneratorConfig{ValidateFunc: "count"}, "must have the signature func() error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenerator(&tt.config, &StructInfo{Methods: methods}).resolveHooks()
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("resolveHooks() error = %v, want it to contain %q", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadStruct type-checks the whole package containing filename and extracts
+// struct information, resolving every field type with go/types.
+// Compared to ParseStruct, the result also carries the resolved type of each
+// field and the imports (including aliased ones) referenced by field types.
+func LoadStruct(filename, structName string) (*StructInfo, error) {
+	structs, err := LoadStructs(filepath.Dir(filename), []string{structName})
+	if err != nil {
+		return nil, err
+	}
+	return structs[0], nil
+}
+
+// LoadStructs type-checks the package in dir with go/packages and extracts
+// information for the named structs, in the order given. If structNames is
+// empty, every struct declared in the package is returned, sorted by name.
+// Files are selected like the go command does, with the build tags of -tags.
+func LoadStructs(dir string, structNames []string) ([]*StructInfo, error) {
+	fset := token.NewFileSet()
+	// Dependencies are type-checked from source as well, which does not
+	// depend on the export data format of the go command in use
+	cfg := &packages.Config{
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:        dir,
+		Fset:       fset,
+		BuildFlags: buildFlags(),
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package %s: %w", dir, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("failed to load package %s: found %d packages; the directory must be part of a Go module", dir, len(pkgs))
+	}
+	pkg := pkgs[0]
+
+	// Keep going on type errors: the package may reference generated code that
+	// does not exist yet, which must not prevent loading the struct itself
+	var typeErrs []error
+	for _, pkgErr := range pkg.Errors {
+		if pkgErr.Kind != packages.TypeError {
+			return nil, fmt.Errorf("failed to load package %s: %w", dir, pkgErr)
+		}
+		typeErrs = append(typeErrs, pkgErr)
+	}
+
+	files := pkg.Syntax
+	generated := map[string]bool{}
+	for _, file := range files {
+		// Files processed by cgo are marked as generated by cgo, but their
+		// line directives point at the files written by hand
+		position := fset.Position(file.Pos())
+		if position.Filename == fset.PositionFor(file.Pos(), false).Filename {
+			generated[position.Filename] = isGeneratedFile(file)
+		}
+	}
+
+	decls, err := selectStructDecls(findStructDecls(fset, files), structNames, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	builders := collectBuilders(files)
+	structInfos := make([]*StructInfo, 0, len(decls))
+	for _, decl := range decls {
+		structInfo, err := newTypedStructInfo(fset, pkg.Types, pkg.TypesInfo, decl.file.Name.Name, decl.spec, typeErrs, generated)
+		if err != nil {
+			return nil, err
+		}
+		structInfo.Builders = builders
+		structInfos = append(structInfos, structInfo)
+	}
+	return structInfos, nil
+}
+
+// buildFlags returns the flags of the go command selecting the files of the
+// -tags build tags
+func buildFlags() []string {
+	if len(buildContext.BuildTags) == 0 {
+		return nil
+	}
+	return []string{"-tags=" + strings.Join(buildContext.BuildTags, ",")}
+}
+
+// newTypedStructInfo extracts struct information from typeSpec and attaches
+// the type information recorded while type-checking pkg. generated reports the
+// files of the package that were generated, keyed by file name.
+func newTypedStructInfo(fset *token.FileSet, pkg *types.Package, info *types.Info, packageName string, typeSpec *ast.TypeSpec, typeErrs []error, generated map[string]bool) (*StructInfo, error) {
+	structName := typeSpec.Name.Name
+
+	obj := pkg.Scope().Lookup(structName)
+	if obj == nil {
+		return nil, fmt.Errorf("struct %s could not be type-checked: %v", structName, typeErrs)
+	}
+	structType, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct type", structName)
+	}
+
+	structInfo, err := newStructInfo(fset, packageName, typeSpec)
+	if err != nil {
+		return nil, err
+	}
+	if len(structInfo.Fields) != structType.NumFields() {
+		return nil, fmt.Errorf("struct %s: field count mismatch between syntax and type information", structName)
+	}
+	for i := range structInfo.Fields {
+		structInfo.Fields[i].TypeInfo = structType.Field(i).Type()
+	}
+	structInfo.Imports = collectImports(info, typeSpec)
+	err = flattenEmbedded(structInfo, func(field FieldInfo) ([]FieldInfo, error) {
+		return typedEmbeddedFields(fset, pkg, field, &structInfo.Imports)
+	})
+	if err != nil {
+		return nil, err
+	}
+	structInfo.Methods = collectTypedMethods(fset, pkg, obj.Type(), generated)
+
+	return structInfo, nil
+}
+
+// typedEmbeddedFields returns the fields of the struct type of the embedded
+// field, for flattening it. Structs of other packages only contribute their
+// exported fields, and the imports their field types reference are added to
+// imports.
+func typedEmbeddedFields(fset *token.FileSet, pkg *types.Package, field FieldInfo, imports *[]ImportInfo) ([]FieldInfo, error) {
+	named, ok := types.Unalias(field.TypeInfo).(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("embedded type %s is not a named struct type", field.Type)
+	}
+	if named.TypeArgs().Len() > 0 {
+		return nil, fmt.Errorf("generic struct %s cannot be flattened", field.Type)
+	}
+	structType, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("embedded type %s is not a struct type", field.Type)
+	}
+
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+	local := named.Obj().Pkg() == pkg
+
+	fields := []FieldInfo{}
+	for i := 0; i < structType.NumFields(); i++ {
+		v := structType.Field(i)
+		if !local && !v.Exported() {
+			continue
+		}
+		position := fset.Position(v.Pos())
+		fieldInfo := FieldInfo{
+			Name:     v.Name(),
+			Type:     types.TypeString(v.Type(), qualifier),
+			Tag:      structType.Tag(i),
+			Exported: v.Exported(),
+			Embedded: v.Embedded(),
+			Pos:      fmt.Sprintf("%s:%d", position.Filename, position.Line),
+			TypeInfo: v.Type(),
+		}
+		if err := applyTagOptions(&fieldInfo); err != nil {
+			return nil, fmt.Errorf("%s: field %s: %w", fieldInfo.Pos, fieldInfo.Name, err)
+		}
+		if !local {
+			addTypeImports(imports, v.Type(), pkg)
+		}
+		fields = append(fields, fieldInfo)
+	}
+	return fields, nil
+}
+
+// addTypeImports adds the packages other than pkg referenced by t to imports,
+// keeping them sorted by path
+func addTypeImports(imports *[]ImportInfo, t types.Type, pkg *types.Package) {
+	var visit func(t types.Type)
+	visit = func(t types.Type) {
+		switch t := t.(type) {
+		case *types.Named:
+			if other := t.Obj().Pkg(); other != nil && other != pkg {
+				imp := ImportInfo{Path: other.Path()}
+				if !slices.ContainsFunc(*imports, func(existing ImportInfo) bool { return existing.Path == imp.Path }) {
+					*imports = append(*imports, imp)
+				}
+			}
+			for i := 0; i < t.TypeArgs().Len(); i++ {
+				visit(t.TypeArgs().At(i))
+			}
+		case *types.Pointer:
+			visit(t.Elem())
+		case *types.Slice:
+			visit(t.Elem())
+		case *types.Array:
+			visit(t.Elem())
+		case *types.Chan:
+			visit(t.Elem())
+		case *types.Map:
+			visit(t.Key())
+			visit(t.Elem())
+		case *types.Signature:
+			for i := 0; i < t.Params().Len(); i++ {
+				visit(t.Params().At(i).Type())
+			}
+			for i := 0; i < t.Results().Len(); i++ {
+				visit(t.Results().At(i).Type())
+			}
+		}
+	}
+	visit(t)
+
+	sort.Slice(*imports, func(i, j int) bool {
+		return (*imports)[i].Path < (*imports)[j].Path
+	})
+}
+
+// collectTypedMethods returns the methods of the method set of *t, including
+// methods promoted from embedded fields, sorted by name
+func collectTypedMethods(fset *token.FileSet, pkg *types.Package, t types.Type, generated map[string]bool) []MethodInfo {
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+	typeStrings := func(tuple *types.Tuple) []string {
+		result := make([]string, tuple.Len())
+		for i := range result {
+			result[i] = types.TypeString(tuple.At(i).Type(), qualifier)
+		}
+		return result
+	}
+
+	methodSet := types.NewMethodSet(types.NewPointer(t))
+	methods := make([]MethodInfo, 0, methodSet.Len())
+	for i := 0; i < methodSet.Len(); i++ {
+		obj := methodSet.At(i).Obj()
+		sig := methodSet.At(i).Type().(*types.Signature)
+		methods = append(methods, MethodInfo{
+			Name:      obj.Name(),
+			Params:    typeStrings(sig.Params()),
+			Results:   typeStrings(sig.Results()),
+			Generated: generated[fset.Position(obj.Pos()).Filename],
+		})
+	}
+	return methods
+}
+
+// collectImports returns the imports referenced by the type parameters and
+// field types of typeSpec, sorted by path
+func collectImports(info *types.Info, typeSpec *ast.TypeSpec) []ImportInfo {
+	seen := map[ImportInfo]bool{}
+	imports := []ImportInfo{}
+
+	ast.Inspect(typeSpec, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		pkgName, ok := info.Uses[ident].(*types.PkgName)
+		if !ok {
+			return true
+		}
+
+		imp := ImportInfo{Path: pkgName.Imported().Path()}
+		if ident.Name != pkgName.Imported().Name() {
+			imp.Name = ident.Name
+		}
+		if !seen[imp] {
+			seen[imp] = true
+			imports = append(imports, imp)
+		}
+		return true
+	})
+
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Path < imports[j].Path
+	})
+	return imports
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadStruct(t *testing.T) {
+	tmpDir := tempModule(t)
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+import (
+	"strings"
+	tm "time"
+)
+
+type Duration = tm.Duration
+
+type Builder = strings.Builder
+
+type TestStruct struct {
+	timeout  Duration
+	deadline tm.Time
+	name     string
+	sb       *Builder
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Other files may use code that has not been generated yet
+	usage := `package test
+
+var _ = NewTestStruct
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "usage.go"), []byte(usage), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := LoadStruct(testFile, "TestStruct")
+	if err != nil {
+		t.Fatalf("LoadStruct failed: %v", err)
+	}
+
+	if len(info.Fields) != 4 {
+		t.Fatalf("Expected 4 fields, got %d", len(info.Fields))
+	}
+
+	// Type strings keep the source spelling, resolved types see through aliases
+	if info.Fields[0].Type != "Duration" {
+		t.Errorf("Expected field type 'Duration', got '%s'", info.Fields[0].Type)
+	}
+	if got := types.TypeString(types.Unalias(info.Fields[0].TypeInfo), nil); got != "time.Duration" {
+		t.Errorf("Expected resolved type 'time.Duration', got '%s'", got)
+	}
+	if basic, ok := info.Fields[0].TypeInfo.Underlying().(*types.Basic); !ok || basic.Kind() != types.Int64 {
+		t.Errorf("Expected underlying type int64, got %v", info.Fields[0].TypeInfo.Underlying())
+	}
+	if _, ok := info.Fields[3].TypeInfo.(*types.Pointer); !ok {
+		t.Errorf("Expected pointer type for sb, got %v", info.Fields[3].TypeInfo)
+	}
+
+	// Only imports referenced by fields are collected, keeping the alias
+	expected := []ImportInfo{{Name: "tm", Path: "time"}}
+	if len(info.Imports) != len(expected) || info.Imports[0] != expected[0] {
+		t.Errorf("Expected imports %v, got %v", expected, info.Imports)
+	}
+}
+
+func TestLoadStructNotFound(t *testing.T) {
+	tmpDir := tempModule(t)
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+type OtherStruct struct {
+	name string
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadStruct(testFile, "NonExistent")
+	if err == nil {
+		t.Error("Expected error when struct not found")
+	}
+}
+
+func TestLoadStructPromotedMethods(t *testing.T) {
+	tmpDir := tempModule(t)
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+import "context"
+
+type base struct{}
+
+func (base) Validate() error { return nil }
+
+type TestStruct struct {
+	base
+	name string
+}
+
+func (s *TestStruct) open(ctx context.Context) error { return nil }
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := LoadStruct(testFile, "TestStruct")
+	if err != nil {
+		t.Fatalf("LoadStruct failed: %v", err)
+	}
+
+	// Methods promoted from embedded fields are part of the method set
+	method, ok := info.Method("Validate")
+	if !ok || len(method.Params) != 0 || len(method.Results) != 1 || method.Results[0] != "error" {
+		t.Errorf("Expected promoted Validate() error, got %+v (found: %v)", method, ok)
+	}
+	method, ok = info.Method("open")
+	if !ok || len(method.Params) != 1 || method.Params[0] != "context.Context" {
+		t.Errorf("Expected open(context.Context) error, got %+v (found: %v)", method, ok)
+	}
+}
+
+func TestLoadStructFlatten(t *testing.T) {
+	tmpDir := tempModule(t)
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+import "net/http"
+
+type base struct {
+	id int
+}
+
+type Session struct {
+	base        ` + "`constructor:\"flatten\"`" + `
+	http.Cookie ` + "`constructor:\"flatten\"`" + `
+	user        string
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := LoadStruct(testFile, "Session")
+	if err != nil {
+		t.Fatalf("LoadStruct failed: %v", err)
+	}
+
+	fields := map[string]FieldInfo{}
+	for _, field := range info.Fields {
+		fields[field.Name] = field
+	}
+
+	// Unexported fields of structs of the package are flattened too
+	if field, ok := fields["id"]; !ok || field.Parent != "base" || field.TypeInfo == nil {
+		t.Errorf("Expected flattened field id of base, got %+v", field)
+	}
+	// Fields of other packages keep their qualified types, and add their imports
+	if field, ok := fields["Expires"]; !ok || field.Parent != "Cookie" || field.Type != "time.Time" {
+		t.Errorf("Expected flattened field Expires of Cookie, got %+v", field)
+	}
+	if field, ok := fields["SameSite"]; !ok || field.Type != "http.SameSite" {
+		t.Errorf("Expected flattened field SameSite of type http.SameSite, got %+v", field)
+	}
+	expected := []ImportInfo{{Path: "net/http"}, {Path: "time"}}
+	if !reflect.DeepEqual(info.Imports, expected) {
+		t.Errorf("Expected imports %v, got %v", expected, info.Imports)
+	}
+}
+
+// tempModule returns a temporary directory holding a Go module, which packages
+// must belong to for go/packages to load them
+func tempModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/test\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadStructBuildTags(t *testing.T) {
+	tmpDir := tempModule(t)
+
+	files := map[string]string{
+		"server.go":        "//go:build !custom\n\npackage test\n\ntype Server struct {\n\thost string\n}\n",
+		"server_custom.go": "//go:build custom\n\npackage test\n\ntype Server struct {\n\tsocket string\n}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := LoadStruct(filepath.Join(tmpDir, "server.go"), "Server")
+	if err != nil {
+		t.Fatalf("LoadStruct failed: %v", err)
+	}
+	if info.Fields[0].Name != "host" {
+		t.Errorf("Expected field host without build tags, got %s", info.Fields[0].Name)
+	}
+
+	// Build tags of -tags select the files that are type-checked
+	defer func(tags []string) { buildContext.BuildTags = tags }(buildContext.BuildTags)
+	buildContext.BuildTags = []string{"custom"}
+
+	info, err = LoadStruct(filepath.Join(tmpDir, "server.go"), "Server")
+	if err != nil {
+		t.Fatalf("LoadStruct failed: %v", err)
+	}
+	if info.Fields[0].Name != "socket" || info.Fields[0].TypeInfo == nil {
+		t.Errorf("Expected typed field socket with the custom tag, got %+v", info.Fields[0])
+	}
+}
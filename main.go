@@ -3,8 +3,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 )
 
@@ -12,17 +15,24 @@ const version = "1.0.0"
 
 func main() {
 	// Define flags
+	baseConfig := &GeneratorConfig{ConstructorTypes: []string{"allArgs"}}
+	registerConfigFlags(flag.CommandLine, baseConfig)
+	flag.Var((*listFlag)(&buildContext.BuildTags), "tags", "[optional] Comma-separated list of build tags to consider when selecting source files")
 	var (
-		typeName         = flag.String("type", "", "[mandatory] The struct type name to generate constructor for")
-		constructorTypes = flag.String("constructorTypes", "allArgs", "[optional] Comma-separated list of constructor types: allArgs,builder,options")
-		outputFile       = flag.String("output", "", "[optional] Output file path (default: <source_dir>/<type>_gen.go)")
-		initFunc         = flag.String("init", "", "[optional] Name of initialization method to call after construction")
-		returnValue      = flag.Bool("returnValue", false, "[optional] Return value instead of pointer")
-		setterPrefix     = flag.String("setterPrefix", "", "[optional] Prefix for setter methods in builder pattern (e.g., 'With')")
-		withGetter       = flag.Bool("withGetter", false, "[optional] Generate getter methods for private fields")
-		showVersion      = flag.Bool("version", false, "[optional] Show version information")
+		typeName    = flag.String("type", "", "[optional] Comma-separated list of struct type names to generate constructors for")
+		all         = flag.Bool("all", false, "[optional] Generate constructors for every struct in the package instead of -type")
+		outputFile  = flag.String("output", "", "[optional] Output file path (default: <source_dir>/<type>_gen.go, or <source_dir>/constructor_gen.go for multiple types)")
+		typed       = flag.Bool("typed", false, "[optional] Type-check the whole package to resolve field types and imports")
+		showVersion = flag.Bool("version", false, "[optional] Show version information")
 	)
 
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: constructor [flags] [packages]\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Without -type or -all, every struct annotated with %s in the given\n", directivePrefix)
+		fmt.Fprintf(flag.CommandLine.Output(), "packages (default: current directory, ./... for all subdirectories) is generated.\n\n")
+		flag.PrintDefaults()
+	}
+
 	flag.Parse()
 
 	// Show version
@@ -31,102 +41,312 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Validate required flags
-	if *typeName == "" {
-		fmt.Fprintf(os.Stderr, "Error: -type flag is mandatory\n\n")
-		flag.Usage()
+	// Validate flags
+	if *typeName != "" && *all {
+		fmt.Fprintf(os.Stderr, "Error: -type and -all are mutually exclusive\n")
+		os.Exit(1)
+	}
+	if *typeName != "" && flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: package arguments cannot be combined with -type\n")
+		os.Exit(1)
+	}
+	if err := validateConfig(baseConfig); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Find the source file containing the struct
-	sourceFile, err := findSourceFile(*typeName)
+	// Parse type names
+	typeNames, err := parseTypeNames(*typeName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if *typeName != "" && len(typeNames) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: -type must name at least one struct\n")
+		os.Exit(1)
+	}
 
-	// Parse the struct
-	structInfo, err := ParseStruct(sourceFile, *typeName)
+	// Find and parse the structs, grouped by package
+	var jobs []*packageJob
+	if len(typeNames) > 0 {
+		jobs, err = loadTypeJobs(typeNames, *typed)
+	} else {
+		jobs, err = loadPackageJobs(flag.Args(), *all, *typed)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing struct: %v\n", err)
 		os.Exit(1)
 	}
-
-	// Determine output file
-	output := *outputFile
-	if output == "" {
-		dir := filepath.Dir(sourceFile)
-		output = filepath.Join(dir, strings.ToLower(*typeName)+"_gen.go")
+	if len(jobs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no structs annotated with %s found; use -type or -all to select structs\n", directivePrefix)
+		os.Exit(1)
 	}
-
-	// Parse constructor types
-	types := strings.Split(*constructorTypes, ",")
-	for i, t := range types {
-		types[i] = strings.TrimSpace(t)
+	if *outputFile != "" {
+		if len(jobs) > 1 {
+			fmt.Fprintf(os.Stderr, "Error: -output cannot be used when generating more than one package\n")
+			os.Exit(1)
+		}
+		jobs[0].output = *outputFile
 	}
 
-	// Validate constructor types
-	for _, t := range types {
-		if t != "allArgs" && t != "builder" && t != "options" {
-			fmt.Fprintf(os.Stderr, "Error: invalid constructor type '%s'. Valid types: allArgs, builder, options\n", t)
+	for _, job := range jobs {
+		if err := generatePackage(job, baseConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating code: %v\n", err)
 			os.Exit(1)
 		}
+		fmt.Printf("Generated constructor code in %s\n", job.output)
+	}
+}
+
+// packageJob is a set of structs of one package generated into a single output file
+type packageJob struct {
+	output  string
+	structs []*StructInfo
+}
+
+// registerConfigFlags registers the flags that configure code generation on fs,
+// storing their values in config. The same flags are accepted by //constructor:gen
+// directives, so every option can be set per struct as well.
+func registerConfigFlags(fs *flag.FlagSet, config *GeneratorConfig) {
+	fs.Var((*listFlag)(&config.ConstructorTypes), "constructorTypes", "[optional] Comma-separated list of constructor types: allArgs,builder,options,stepBuilder,withers")
+	fs.StringVar(&config.InitFunc, "init", config.InitFunc, "[optional] Name of initialization method to call after construction; it may take a context.Context and return an error")
+	fs.StringVar(&config.ValidateFunc, "validate", config.ValidateFunc, "[optional] Name of validation method returning an error to call after construction (default: Validate if declared, '-' to disable)")
+	fs.BoolVar(&config.ReturnValue, "returnValue", config.ReturnValue, "[optional] Return value instead of pointer")
+	fs.StringVar(&config.OptionStyle, "optionStyle", config.OptionStyle, "[optional] Option type of the options constructor: func (type XOption func(*X)), interface (type XOption interface{ apply(*X) }) or error (type XOption func(*X) error)")
+	fs.StringVar(&config.OptionNaming, "optionNaming", config.OptionNaming, "[optional] Option function naming: field (WithPort), prefixed (WithServerPort), namespace (ServerOpts.Port) or auto (prefixed when names clash)")
+	fs.StringVar(&config.SetterPrefix, "setterPrefix", config.SetterPrefix, "[optional] Prefix for setter methods in builder pattern (e.g., 'With')")
+	fs.BoolVar(&config.ToBuilder, "toBuilder", config.ToBuilder, "[optional] Generate NewXBuilderFrom and a ToBuilder method creating builders from existing instances")
+	fs.BoolVar(&config.WithGetter, "withGetter", config.WithGetter, "[optional] Generate getter methods for private fields")
+	fs.StringVar(&config.GetterStyle, "getterStyle", config.GetterStyle, "[optional] Getter naming: get (GetName) or plain (Name)")
+	fs.StringVar(&config.Receiver, "receiver", config.Receiver, "[optional] Receiver of getter methods: pointer or value")
+	fs.BoolVar(&config.WithSetter, "withSetter", config.WithSetter, "[optional] Generate setter methods for private fields")
+	fs.BoolVar(&config.FluentSetter, "fluentSetter", config.FluentSetter, "[optional] Setter methods return the receiver for chaining")
+	fs.BoolVar(&config.SetterChecks, "setterChecks", config.SetterChecks, "[optional] Setter methods reject zero values of required fields and check field rules, returning an error")
+	fs.BoolVar(&config.WithersDeepCopy, "withersDeepCopy", config.WithersDeepCopy, "[optional] Clone slice and map fields in wither methods so copies do not share them")
+	fs.BoolVar(&config.CollectionHelpers, "collectionHelpers", config.CollectionHelpers, "[optional] Generate AddX/PutX builder methods and WithX options adding items to slice and map fields")
+	fs.BoolVar(&config.NestedBuilders, "nestedBuilders", config.NestedBuilders, "[optional] Generate ConfigureX builder methods setting struct fields with the builders of their types")
+	fs.BoolVar(&config.AllArgsDefaults, "allArgsDefaults", config.AllArgsDefaults, "[optional] Replace zero arguments of the allArgs constructor with the field defaults")
+	fs.BoolVar(&config.OmitDeprecated, "omitDeprecated", config.OmitDeprecated, "[optional] Leave fields whose doc has a Deprecated: paragraph out of the allArgs constructor parameters")
+}
+
+// validateConfig checks a generator configuration for invalid settings
+func validateConfig(config *GeneratorConfig) error {
+	if len(config.ConstructorTypes) == 0 {
+		return fmt.Errorf("at least one constructor type is required")
+	}
+	for _, t := range config.ConstructorTypes {
+		if t != "allArgs" && t != "builder" && t != "options" && t != "stepBuilder" && t != "withers" {
+			return fmt.Errorf("invalid constructor type '%s'. Valid types: allArgs, builder, options, stepBuilder, withers", t)
+		}
+	}
+	if config.OptionStyle != "" && !slices.Contains(optionStyles, config.OptionStyle) {
+		return fmt.Errorf("invalid option style '%s'. Valid styles: %s", config.OptionStyle, strings.Join(optionStyles, ", "))
+	}
+	if config.OptionNaming != "" && !slices.Contains(optionNamings, config.OptionNaming) {
+		return fmt.Errorf("invalid option naming '%s'. Valid namings: %s", config.OptionNaming, strings.Join(optionNamings, ", "))
+	}
+	if config.ToBuilder && !slices.Contains(config.ConstructorTypes, "builder") {
+		return fmt.Errorf("-toBuilder requires the builder constructor type")
 	}
+	if config.NestedBuilders && !slices.Contains(config.ConstructorTypes, "builder") {
+		return fmt.Errorf("-nestedBuilders requires the builder constructor type")
+	}
+	if config.OmitDeprecated && !slices.Contains(config.ConstructorTypes, "allArgs") {
+		return fmt.Errorf("-omitDeprecated requires the allArgs constructor type")
+	}
+	if config.GetterStyle != "" && !slices.Contains(getterStyles, config.GetterStyle) {
+		return fmt.Errorf("invalid getter style '%s'. Valid styles: %s", config.GetterStyle, strings.Join(getterStyles, ", "))
+	}
+	if config.Receiver != "" && !slices.Contains(receiverKinds, config.Receiver) {
+		return fmt.Errorf("invalid receiver '%s'. Valid receivers: %s", config.Receiver, strings.Join(receiverKinds, ", "))
+	}
+	// Both generate New<Type>Builder
+	if slices.Contains(config.ConstructorTypes, "builder") && slices.Contains(config.ConstructorTypes, "stepBuilder") {
+		return fmt.Errorf("constructor types builder and stepBuilder cannot be combined")
+	}
+	return nil
+}
 
-	// Create generator config
-	config := &GeneratorConfig{
-		StructName:       *typeName,
-		ConstructorTypes: types,
-		OutputFile:       output,
-		InitFunc:         *initFunc,
-		ReturnValue:      *returnValue,
-		SetterPrefix:     *setterPrefix,
-		WithGetter:       *withGetter,
+// generatePackage generates the code for all structs of job into its output file.
+// Each struct is configured by baseConfig overridden by its own //constructor:gen directive.
+func generatePackage(job *packageJob, baseConfig *GeneratorConfig) error {
+	generators := make([]*Generator, 0, len(job.structs))
+	for _, structInfo := range job.structs {
+		config, err := applyDirective(baseConfig, structInfo.Directive)
+		if err != nil {
+			return fmt.Errorf("struct %s: %w", structInfo.Name, err)
+		}
+		if err := validateConfig(config); err != nil {
+			return fmt.Errorf("struct %s: %w", structInfo.Name, err)
+		}
+		config.StructName = structInfo.Name
+		config.OutputFile = job.output
+		generators = append(generators, NewGenerator(config, structInfo))
 	}
 
-	// Generate code
-	generator := NewGenerator(config, structInfo)
-	code, err := generator.Generate()
+	code, err := GenerateFile(generators)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating code: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Write to file
-	if err := os.WriteFile(output, []byte(code), 0644); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
-		os.Exit(1)
+	if err := os.WriteFile(job.output, []byte(code), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// listFlag is a flag.Value holding a comma-separated list
+type listFlag []string
+
+func (l *listFlag) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*l = items
+	return nil
+}
+
+// parseTypeNames splits a comma-separated list of type names, rejecting duplicates
+func parseTypeNames(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
 	}
 
-	fmt.Printf("Generated constructor code in %s\n", output)
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("type %s is listed more than once", name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
 }
 
-// findSourceFile searches for a Go file containing the struct definition
-func findSourceFile(typeName string) (string, error) {
-	// First, check if GOFILE environment variable is set (set by go generate)
-	if gofile := os.Getenv("GOFILE"); gofile != "" {
-		return gofile, nil
+// loadTypeJobs finds and parses the named structs in the package of the current
+// directory, looking through all of its source files regardless of which file
+// contains the go:generate directive.
+// A single type is generated into <type>_gen.go, several into constructor_gen.go.
+func loadTypeJobs(typeNames []string, typed bool) ([]*packageJob, error) {
+	sourceDir := "."
+
+	job := &packageJob{output: filepath.Join(sourceDir, "constructor_gen.go")}
+	if len(typeNames) == 1 {
+		job.output = filepath.Join(sourceDir, strings.ToLower(typeNames[0])+"_gen.go")
+	}
+
+	var err error
+	if typed {
+		job.structs, err = LoadStructs(sourceDir, typeNames)
+	} else {
+		job.structs, err = ParseDir(sourceDir, typeNames)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return []*packageJob{job}, nil
+}
 
-	// Otherwise, search current directory for .go files
-	files, err := filepath.Glob("*.go")
+// loadPackageJobs parses the packages matching patterns (default: the current
+// directory) and selects every struct when all is set, or only the structs
+// annotated with //constructor:gen otherwise. Packages without selected
+// structs are skipped.
+func loadPackageJobs(patterns []string, all, typed bool) ([]*packageJob, error) {
+	dirs, err := expandPackagePatterns(patterns)
 	if err != nil {
-		return "", fmt.Errorf("failed to list Go files: %w", err)
+		return nil, err
+	}
+
+	jobs := []*packageJob{}
+	for _, dir := range dirs {
+		var structInfos []*StructInfo
+		if typed {
+			structInfos, err = LoadStructs(dir, nil)
+		} else {
+			structInfos, err = ParseDir(dir, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		job := &packageJob{output: filepath.Join(dir, "constructor_gen.go")}
+		for _, structInfo := range structInfos {
+			if all || structInfo.Annotated {
+				job.structs = append(job.structs, structInfo)
+			}
+		}
+		if len(job.structs) > 0 {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// expandPackagePatterns resolves package directory patterns into a sorted list of
+// directories containing Go files. A pattern ending in "/..." matches the directory
+// and all its subdirectories, skipping testdata, vendor and hidden directories.
+func expandPackagePatterns(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	seen := map[string]bool{}
+	dirs := []string{}
+	addDir := func(dir string) error {
+		files, err := packageSourceFiles(dir)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+		return nil
 	}
 
-	// Try to find the struct in each file
-	for _, file := range files {
-		// Skip generated files
-		if strings.HasSuffix(file, "_gen.go") {
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(pattern, "...")
+		if !recursive {
+			if err := addDir(filepath.Clean(pattern)); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
-		// Try to parse the file
-		_, err := ParseStruct(file, typeName)
-		if err == nil {
-			return file, nil
+		root = filepath.Clean(root)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return addDir(path)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", pattern, err)
 		}
 	}
 
-	return "", fmt.Errorf("could not find struct %s in current directory", typeName)
+	sort.Strings(dirs)
+	return dirs, nil
 }
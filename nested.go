@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// resolveBuilders determines the builders each generator can nest: the ones
+// declared in the package, updated with the builders generated along with it,
+// which replace their previous versions. Nested builders build their struct
+// within a builder method, so builders whose Build returns an error or takes
+// a context cannot be nested. Hooks must be resolved first.
+func resolveBuilders(generators []*Generator) {
+	generated := map[string]BuilderInfo{}
+	unusable := map[string]bool{}
+	for _, g := range generators {
+		if !slices.Contains(g.config.ConstructorTypes, "builder") {
+			continue
+		}
+		canFail := len(requiredFields(g.info.GetFieldsForConstructor())) > 0 || g.hooks.returnsError()
+		if canFail || g.hooks.initContext || len(g.info.TypeParams) > 0 {
+			unusable[g.info.Name] = true
+			continue
+		}
+		generated[g.info.Name] = BuilderInfo{ReturnsValue: g.config.ReturnValue}
+	}
+
+	for _, g := range generators {
+		g.builders = map[string]BuilderInfo{}
+		for name, builder := range g.info.Builders {
+			if !unusable[name] {
+				g.builders[name] = builder
+			}
+		}
+		maps.Copy(g.builders, generated)
+	}
+}
+
+// generateNestedBuilders generates a Configure<Field> builder method for each
+// field whose type, or the type it points to, is a struct with a builder. The
+// method passes a new builder of the field type to a function configuring it
+// and sets the field to the struct built.
+func (g *Generator) generateNestedBuilders(fields []FieldInfo, builderType string) string {
+	var buf bytes.Buffer
+
+	for _, field := range fields {
+		typeName, isPointer := strings.CutPrefix(field.Type, "*")
+		builder, ok := g.builders[typeName]
+		if !ok {
+			continue
+		}
+
+		methodName := "Configure" + toUpperCamelCase(field.APIName())
+		fieldName := toLowerCamelCase(field.Name)
+		nestedType := typeName + "Builder"
+
+		buf.WriteString(fmt.Sprintf("// %s sets the %s field to a %s built by configure\n", methodName, field.Name, typeName))
+		buf.WriteString(deprecatedComment(field, ""))
+		buf.WriteString(fmt.Sprintf("func (b *%s) %s(configure func(*%s)) *%s {\n", builderType, methodName, nestedType, builderType))
+		buf.WriteString(fmt.Sprintf("\tnested := New%s()\n", nestedType))
+		buf.WriteString("\tconfigure(nested)\n")
+		switch {
+		case isPointer && builder.ReturnsValue:
+			buf.WriteString("\tv := nested.Build()\n")
+			buf.WriteString(fmt.Sprintf("\tb.%s = &v\n", fieldName))
+		case !isPointer && !builder.ReturnsValue:
+			buf.WriteString(fmt.Sprintf("\tb.%s = *nested.Build()\n", fieldName))
+		default:
+			buf.WriteString(fmt.Sprintf("\tb.%s = nested.Build()\n", fieldName))
+		}
+		if field.Required {
+			buf.WriteString(fmt.Sprintf("\tb.%sSet = true\n", fieldName))
+		}
+		buf.WriteString(g.presenceMark("\t", "b", field))
+		buf.WriteString("\treturn b\n")
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.String()
+}
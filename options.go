@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+)
+
+// Option styles selected with -optionStyle
+const (
+	optionStyleFunc      = "func"      // type XOption func(*X)
+	optionStyleInterface = "interface" // type XOption interface{ apply(*X) }
+	optionStyleError     = "error"     // type XOption func(*X) error
+)
+
+// optionStyles lists the valid values of -optionStyle
+var optionStyles = []string{optionStyleFunc, optionStyleInterface, optionStyleError}
+
+// Option function naming strategies selected with -optionNaming
+const (
+	optionNamingField     = "field"     // WithPort
+	optionNamingPrefixed  = "prefixed"  // WithServerPort
+	optionNamingNamespace = "namespace" // ServerOpts.Port
+	optionNamingAuto      = "auto"      // field, or prefixed if the names clash with another struct
+)
+
+// optionNamings lists the valid values of -optionNaming
+var optionNamings = []string{optionNamingField, optionNamingPrefixed, optionNamingNamespace, optionNamingAuto}
+
+// resolveOptionNaming determines the option function naming of each generator
+// generating options, choosing between field and prefixed names for auto.
+// Returns an error if option functions of different structs would clash.
+func resolveOptionNaming(generators []*Generator) error {
+	// Names taken by each struct with field naming
+	owners := map[string][]string{}
+	for _, g := range generators {
+		g.optionNaming = g.config.OptionNaming
+		if g.optionNaming == "" {
+			g.optionNaming = optionNamingField
+		}
+		if !slices.Contains(g.config.ConstructorTypes, "options") {
+			continue
+		}
+		if g.optionNaming == optionNamingNamespace && len(g.info.TypeParams) > 0 {
+			return fmt.Errorf("%s: namespace option naming does not support generic structs", g.info.Name)
+		}
+		for _, field := range g.optionNameFields() {
+			name := "With" + toUpperCamelCase(field.APIName())
+			owners[name] = append(owners[name], g.info.Name)
+		}
+	}
+
+	for _, g := range generators {
+		if g.optionNaming != optionNamingAuto {
+			continue
+		}
+		g.optionNaming = optionNamingField
+		for _, field := range g.optionNameFields() {
+			if len(owners["With"+toUpperCamelCase(field.APIName())]) > 1 {
+				g.optionNaming = optionNamingPrefixed
+				break
+			}
+		}
+	}
+
+	// Check the names actually generated
+	seen := map[string]string{}
+	for _, g := range generators {
+		if !slices.Contains(g.config.ConstructorTypes, "options") || g.optionNaming == optionNamingNamespace {
+			continue
+		}
+		for _, field := range g.optionNameFields() {
+			name := g.optionFuncName(field)
+			if other, ok := seen[name]; ok && other != g.info.Name {
+				return fmt.Errorf("option function %s is generated for both %s and %s; use -optionNaming=prefixed, namespace or auto", name, other, g.info.Name)
+			}
+			seen[name] = g.info.Name
+		}
+	}
+	return nil
+}
+
+// optionNameFields returns the fields naming option functions: the constructor
+// fields and, with -collectionHelpers, their items
+func (g *Generator) optionNameFields() []FieldInfo {
+	fields := g.info.GetFieldsForConstructor()
+	// Invalid items are reported when generating the options
+	helpers, _ := g.collectionHelpers(fields)
+	for _, helper := range helpers {
+		fields = append(fields, helper.itemField())
+	}
+	return fields
+}
+
+// optionFuncName returns the name of the option function of field, e.g.,
+// "WithPort", or the method name "Port" with namespace naming
+func (g *Generator) optionFuncName(field FieldInfo) string {
+	switch g.optionNaming {
+	case optionNamingPrefixed:
+		return "With" + g.info.Name + toUpperCamelCase(field.APIName())
+	case optionNamingNamespace:
+		return toUpperCamelCase(field.APIName())
+	default:
+		return "With" + toUpperCamelCase(field.APIName())
+	}
+}
+
+// optionFuncRef returns how callers refer to the option function of field,
+// e.g., "WithPort" or "ServerOpts.Port"
+func (g *Generator) optionFuncRef(field FieldInfo) string {
+	if g.optionNaming == optionNamingNamespace {
+		return g.info.Name + "Opts." + g.optionFuncName(field)
+	}
+	return g.optionFuncName(field)
+}
+
+// optionFuncDecl returns the declaration of the option function of field up to
+// its parameters, e.g., "func WithPort" or "func (serverOptions) Port"
+func (g *Generator) optionFuncDecl(field FieldInfo) string {
+	if g.optionNaming == optionNamingNamespace {
+		return fmt.Sprintf("func (%s) %s", g.optionNamespaceType(), g.optionFuncName(field))
+	}
+	return fmt.Sprintf("func %s%s", g.optionFuncName(field), g.info.TypeParamsDecl())
+}
+
+// optionNamespaceType returns the type of the namespace value grouping the option functions
+func (g *Generator) optionNamespaceType() string {
+	return toLowerCamelCase(g.info.Name) + "Options"
+}
+
+// generateOptionNamespace generates the namespace value whose methods are the
+// option functions with namespace naming, or returns an empty string
+func (g *Generator) generateOptionNamespace() string {
+	if g.optionNaming != optionNamingNamespace {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("// %s groups the options of %s\n", g.optionNamespaceType(), g.info.Name))
+	buf.WriteString(fmt.Sprintf("type %s struct{}\n\n", g.optionNamespaceType()))
+	buf.WriteString(fmt.Sprintf("// %sOpts provides the option functions of %s\n", g.info.Name, g.info.Name))
+	buf.WriteString(fmt.Sprintf("var %sOpts %s\n\n", g.info.Name, g.optionNamespaceType()))
+	return buf.String()
+}
+
+// generateInterfaceOptions generates an option interface with an unexported
+// apply method, as used by grpc-go and zap. Each With<Field> function returns
+// a value of its own unexported type, so options can be compared and printed.
+// The exported <Type>OptionFunc adapter lets other packages implement options.
+func (g *Generator) generateInterfaceOptions(fields []FieldInfo) string {
+	var buf bytes.Buffer
+
+	optionName := g.info.Name + "Option"
+	optionType := optionName + g.info.TypeArgs()
+	adapterName := optionName + "Func"
+	typeParams := g.info.TypeParamsDecl()
+	typeArgs := g.info.TypeArgs()
+
+	// Generate option interface
+	buf.WriteString(fmt.Sprintf("// %s configures a %s\n", optionName, g.info.Name))
+	buf.WriteString(g.structDocComment())
+	buf.WriteString(fmt.Sprintf("type %s%s interface {\n", optionName, typeParams))
+	buf.WriteString(fmt.Sprintf("\tapply(*%s)\n", g.typeName()))
+	buf.WriteString("}\n\n")
+
+	// Generate function adapter
+	buf.WriteString(fmt.Sprintf("// %s adapts a function to a %s\n", adapterName, optionName))
+	buf.WriteString(fmt.Sprintf("type %s%s func(*%s)\n\n", adapterName, typeParams, g.typeName()))
+	buf.WriteString(fmt.Sprintf("func (f %s%s) apply(s *%s) {\n", adapterName, typeArgs, g.typeName()))
+	buf.WriteString("\tf(s)\n")
+	buf.WriteString("}\n\n")
+
+	// Generate one option type per field
+	for _, field := range fields {
+		funcName := g.optionFuncRef(field)
+		paramName := toLowerCamelCase(field.APIName())
+		implName := toLowerCamelCase(g.info.Name) + toUpperCamelCase(field.APIName()) + "Option"
+		implType := implName + typeArgs
+
+		buf.WriteString(fmt.Sprintf("// %s is the %s returned by %s\n", implName, optionName, funcName))
+		buf.WriteString(fmt.Sprintf("type %s%s struct {\n", implName, typeParams))
+		buf.WriteString(fmt.Sprintf("\t%s %s\n", paramName, field.Type))
+		buf.WriteString("}\n\n")
+
+		buf.WriteString(fmt.Sprintf("func (o %s) apply(s *%s) {\n", implType, g.typeName()))
+		buf.WriteString(fmt.Sprintf("\ts.%s = o.%s\n", field.Path(), paramName))
+		buf.WriteString(g.presenceMark("\t", "s", field))
+		buf.WriteString("}\n\n")
+
+		buf.WriteString("// String describes the option, e.g., for logging\n")
+		buf.WriteString(fmt.Sprintf("func (o %s) String() string {\n", implType))
+		buf.WriteString(fmt.Sprintf("\treturn fmt.Sprintf(\"%s(%%v)\", o.%s)\n", funcName, paramName))
+		buf.WriteString("}\n\n")
+
+		buf.WriteString(fmt.Sprintf("// %s sets the %s field\n", g.optionFuncName(field), field.Name))
+		buf.WriteString(docComment(field, ""))
+		buf.WriteString(fmt.Sprintf("%s(%s %s) %s {\n", g.optionFuncDecl(field), paramName, field.Type, optionType))
+		buf.WriteString(fmt.Sprintf("\treturn %s{%s: %s}\n", implType, paramName, paramName))
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.String()
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/printer"
 	"go/token"
-	"reflect"
+	"go/types"
 	"strings"
 )
 
@@ -17,183 +19,442 @@ func ParseStruct(filename, structName string) (*StructInfo, error) {
 		return nil, fmt.Errorf("failed to parse file: %w", err)
 	}
 
-	var structInfo *StructInfo
+	typeSpec := findStructSpec(node, structName)
+	if typeSpec == nil {
+		return nil, fmt.Errorf("struct %s not found in file %s", structName, filename)
+	}
 
-	ast.Inspect(node, func(n ast.Node) bool {
-		// Look for type declarations
-		typeSpec, ok := n.(*ast.TypeSpec)
-		if !ok {
-			return true
+	structInfo, err := newStructInfo(fset, node.Name.Name, typeSpec)
+	if err != nil {
+		return nil, err
+	}
+	err = flattenEmbedded(structInfo, func(field FieldInfo) ([]FieldInfo, error) {
+		spec := findStructSpec(node, field.Type)
+		if spec == nil {
+			return nil, fmt.Errorf("struct %s not found in file %s", field.Type, filename)
 		}
+		return embeddedStructFields(fset, node.Name.Name, spec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	structInfo.Methods = collectMethods([]*ast.File{node}, structName)
+	structInfo.Builders = collectBuilders([]*ast.File{node})
+	return structInfo, nil
+}
 
-		// Check if it's the struct we're looking for
-		if typeSpec.Name.Name != structName {
-			return true
+// ParseDir parses the package in dir and extracts information for the named
+// structs, in the order given. If structNames is empty, every struct declared
+// in the package is returned, sorted by name. Test files, generated files and
+// files excluded by build constraints are ignored.
+func ParseDir(dir string, structNames []string) ([]*StructInfo, error) {
+	fset := token.NewFileSet()
+	files, err := parseSourceDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	allDecls := findStructDecls(fset, files)
+	decls, err := selectStructDecls(allDecls, structNames, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Embedded structs are looked up among the structs of the package
+	lookup := func(field FieldInfo) ([]FieldInfo, error) {
+		candidates := allDecls[field.Type]
+		if len(candidates) != 1 {
+			return nil, fmt.Errorf("struct %s not found in package %s; use -typed for structs of other packages", field.Type, dir)
 		}
+		return embeddedStructFields(fset, candidates[0].file.Name.Name, candidates[0].spec)
+	}
 
-		// Check if it's a struct type
-		structType, ok := typeSpec.Type.(*ast.StructType)
-		if !ok {
-			return true
+	builders := collectBuilders(files)
+	structInfos := make([]*StructInfo, 0, len(decls))
+	for _, decl := range decls {
+		structInfo, err := newStructInfo(fset, decl.file.Name.Name, decl.spec)
+		if err != nil {
+			return nil, err
+		}
+		if err := flattenEmbedded(structInfo, lookup); err != nil {
+			return nil, err
 		}
+		structInfo.Methods = collectMethods(files, structInfo.Name)
+		structInfo.Builders = builders
+		structInfos = append(structInfos, structInfo)
+	}
+	return structInfos, nil
+}
 
-		// Extract struct information
-		structInfo = &StructInfo{
-			Name:        structName,
-			PackageName: node.Name.Name,
-			Fields:      []FieldInfo{},
+// structSpecs returns the type specs of all top-level structs declared in file
+func structSpecs(file *ast.File) []*ast.TypeSpec {
+	specs := []*ast.TypeSpec{}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
 		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if _, ok := typeSpec.Type.(*ast.StructType); !ok || typeSpec.Assign.IsValid() {
+				continue
+			}
+			// The doc comment of an ungrouped declaration is attached to the GenDecl
+			if typeSpec.Doc == nil && !genDecl.Lparen.IsValid() {
+				typeSpec.Doc = genDecl.Doc
+			}
+			specs = append(specs, typeSpec)
+		}
+	}
+	return specs
+}
 
-		// Parse each field
-		for _, field := range structType.Fields.List {
-			fieldType := exprToString(field.Type)
+// findStructSpec returns the type spec declaring the named struct in file, or nil
+func findStructSpec(file *ast.File, structName string) *ast.TypeSpec {
+	for _, typeSpec := range structSpecs(file) {
+		if typeSpec.Name.Name == structName {
+			return typeSpec
+		}
+	}
+	return nil
+}
 
-			// Get tag if exists
-			var tag string
-			if field.Tag != nil {
-				tag = field.Tag.Value
-			}
+// newStructInfo extracts struct information from a struct type spec.
+// Invalid field tags are reported with the position of the field.
+func newStructInfo(fset *token.FileSet, packageName string, typeSpec *ast.TypeSpec) (*StructInfo, error) {
+	structType := typeSpec.Type.(*ast.StructType)
+
+	// Extract struct information
+	structInfo := &StructInfo{
+		Name:        typeSpec.Name.Name,
+		PackageName: packageName,
+		Fields:      []FieldInfo{},
+	}
+
+	// Look for a //constructor:gen directive in the struct doc comment, which
+	// Text leaves out of the doc
+	structInfo.Directive, structInfo.Annotated = findDirective(typeSpec.Doc)
+	structInfo.Doc = strings.TrimSpace(typeSpec.Doc.Text())
 
-			// Parse field skip options
-			skip, skipGetter, skipSetter := parseFieldSkipTags(tag)
-
-			// Handle embedded fields (no name)
-			if len(field.Names) == 0 {
-				structInfo.Fields = append(structInfo.Fields, FieldInfo{
-					Name:       fieldType, // Use type as name for embedded fields
-					Type:       fieldType,
-					Tag:        tag,
-					Exported:   true, // Embedded fields are always exported
-					Skip:       skip,
-					SkipGetter: skipGetter,
-					SkipSetter: skipSetter,
+	// Parse type parameters of generic structs
+	if typeSpec.TypeParams != nil {
+		for _, param := range typeSpec.TypeParams.List {
+			constraint := exprToString(param.Type)
+			for _, name := range param.Names {
+				structInfo.TypeParams = append(structInfo.TypeParams, TypeParam{
+					Name:       name.Name,
+					Constraint: constraint,
 				})
-				continue
 			}
+		}
+	}
+
+	// Parse each field
+	for _, field := range structType.Fields.List {
+		fieldType := exprToString(field.Type)
+		position := fset.Position(field.Pos())
+		pos := fmt.Sprintf("%s:%d", position.Filename, position.Line)
+
+		// Get tag if exists
+		var tag string
+		if field.Tag != nil {
+			tag = field.Tag.Value
+		}
 
-			// Regular fields
+		// Embedded fields are named after their type
+		names := []string{embeddedFieldName(field.Type)}
+		if len(field.Names) > 0 {
+			names = names[:0]
 			for _, name := range field.Names {
-				exported := ast.IsExported(name.Name)
-				structInfo.Fields = append(structInfo.Fields, FieldInfo{
-					Name:       name.Name,
-					Type:       fieldType,
-					Tag:        tag,
-					Exported:   exported,
-					Skip:       skip,
-					SkipGetter: skipGetter,
-					SkipSetter: skipSetter,
-				})
+				names = append(names, name.Name)
 			}
 		}
 
-		return false // Found the struct, stop searching
-	})
+		for _, name := range names {
+			fieldInfo := FieldInfo{
+				Name:     name,
+				Type:     fieldType,
+				Tag:      tag,
+				Exported: ast.IsExported(name),
+				Embedded: len(field.Names) == 0,
+				Pos:      pos,
+				Doc:      fieldDoc(field),
+			}
+			fieldInfo.Deprecated = deprecationNotice(fieldInfo.Doc)
 
-	if structInfo == nil {
-		return nil, fmt.Errorf("struct %s not found in file %s", structName, filename)
+			// Parse constructor tag options
+			if err := applyTagOptions(&fieldInfo); err != nil {
+				return nil, fmt.Errorf("%s: field %s: %w", pos, name, err)
+			}
+
+			structInfo.Fields = append(structInfo.Fields, fieldInfo)
+		}
 	}
 
 	return structInfo, nil
 }
 
-// exprToString converts an ast.Expr to its string representation
-func exprToString(expr ast.Expr) string {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		return t.Name
-	case *ast.StarExpr:
-		return "*" + exprToString(t.X)
-	case *ast.ArrayType:
-		if t.Len == nil {
-			return "[]" + exprToString(t.Elt)
-		}
-		return "[" + exprToString(t.Len) + "]" + exprToString(t.Elt)
-	case *ast.MapType:
-		return "map[" + exprToString(t.Key) + "]" + exprToString(t.Value)
-	case *ast.ChanType:
-		switch t.Dir {
-		case ast.SEND:
-			return "chan<- " + exprToString(t.Value)
-		case ast.RECV:
-			return "<-chan " + exprToString(t.Value)
-		default:
-			return "chan " + exprToString(t.Value)
-		}
-	case *ast.SelectorExpr:
-		return exprToString(t.X) + "." + t.Sel.Name
-	case *ast.InterfaceType:
-		if len(t.Methods.List) == 0 {
-			return "interface{}"
-		}
-		return "interface{...}"
-	case *ast.FuncType:
-		return "func(...)"
-	case *ast.StructType:
-		return "struct{...}"
-	case *ast.Ellipsis:
-		return "..." + exprToString(t.Elt)
-	case *ast.BasicLit:
-		return t.Value
-	default:
-		// Fallback for unknown types
-		return fmt.Sprintf("%v", reflect.TypeOf(expr).Elem().Name())
+// fieldDoc returns the text of the doc comment of field, or of its line
+// comment if it has no doc comment
+func fieldDoc(field *ast.Field) string {
+	doc := field.Doc
+	if doc == nil {
+		doc = field.Comment
 	}
+	return strings.TrimSpace(doc.Text())
 }
 
-// parseFieldSkipTags parses field tags to determine skip behavior
-// Returns: (skip, skipGetter, skipSetter)
-// - skip: completely skip this field (constructor:"-" or newc:"-" or gonstructor:"-")
-// - skipGetter: skip getter generation only (constructor:"getter:false")
-// - skipSetter: skip setter/constructor parameter only (constructor:"setter:false")
-func parseFieldSkipTags(tag string) (bool, bool, bool) {
-	if tag == "" {
-		return false, false, false
+// deprecationNotice returns the notice of the paragraph of doc starting with
+// "Deprecated: ", as recognized by staticcheck and gopls, or an empty string
+// if doc has no such paragraph
+func deprecationNotice(doc string) string {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		if notice, ok := strings.CutPrefix(paragraph, "Deprecated: "); ok {
+			return strings.Join(strings.Fields(notice), " ")
+		}
 	}
+	return ""
+}
 
-	// Remove backticks
-	tag = strings.Trim(tag, "`")
+// embeddedFieldName returns the name of an embedded field of the given type:
+// the unqualified type name without pointer or type arguments, e.g., "Base"
+// for "*pkg.Base[T]"
+func embeddedFieldName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		expr = sel.Sel
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return exprToString(expr)
+}
+
+// embeddedStructFields returns the fields of the struct declared by spec, for
+// flattening it into the structs embedding it
+func embeddedStructFields(fset *token.FileSet, packageName string, spec *ast.TypeSpec) ([]FieldInfo, error) {
+	if spec.TypeParams != nil {
+		return nil, fmt.Errorf("generic struct %s cannot be flattened", spec.Name.Name)
+	}
+	structInfo, err := newStructInfo(fset, packageName, spec)
+	if err != nil {
+		return nil, err
+	}
+	return structInfo.Fields, nil
+}
 
-	skip := false
-	skipGetter := false
-	skipSetter := false
+// flattenEmbedded inserts the fields of the embedded structs tagged
+// constructor:"flatten" after the embedded field, with Parent set to it, so
+// that constructors set them individually. lookup returns the fields of the
+// struct type of an embedded field.
+func flattenEmbedded(structInfo *StructInfo, lookup func(FieldInfo) ([]FieldInfo, error)) error {
+	names := map[string]bool{}
+	for _, field := range structInfo.Fields {
+		names[field.Name] = true
+	}
 
-	// Split by spaces to get individual tags
-	parts := strings.Fields(tag)
-	for _, part := range parts {
-		// Check for constructor tag with options
-		if strings.HasPrefix(part, "constructor:") {
-			tagValue := strings.TrimPrefix(part, "constructor:")
-			tagValue = strings.Trim(tagValue, `"`)
+	fields := make([]FieldInfo, 0, len(structInfo.Fields))
+	for _, field := range structInfo.Fields {
+		fields = append(fields, field)
+		if !field.Flatten {
+			continue
+		}
+		if !field.Embedded {
+			return fmt.Errorf("%s: field %s: constructor tag option \"flatten\" requires an embedded struct", field.Pos, field.Name)
+		}
+		if strings.HasPrefix(field.Type, "*") {
+			return fmt.Errorf("%s: field %s: constructor tag option \"flatten\" requires a struct embedded by value, not a pointer", field.Pos, field.Name)
+		}
 
-			if tagValue == "-" {
-				skip = true
-			} else if strings.Contains(tagValue, "getter:false") {
-				skipGetter = true
-			} else if strings.Contains(tagValue, "setter:false") {
-				skipSetter = true
+		inner, err := lookup(field)
+		if err != nil {
+			return fmt.Errorf("%s: field %s: %w", field.Pos, field.Name, err)
+		}
+		for _, innerField := range inner {
+			if innerField.Flatten {
+				return fmt.Errorf("%s: field %s: flattened struct %s flattens %s in turn, which is not supported", field.Pos, field.Name, field.Type, innerField.Name)
 			}
+			if innerField.Presence {
+				return fmt.Errorf("%s: field %s: flattened struct %s tracks presence in %s, which is not supported", field.Pos, field.Name, field.Type, innerField.Name)
+			}
+			if names[innerField.Name] {
+				return fmt.Errorf("%s: field %s of flattened struct %s clashes with field %s of %s", innerField.Pos, innerField.Name, field.Type, innerField.Name, structInfo.Name)
+			}
+			names[innerField.Name] = true
+			innerField.Parent = field.Name
+			fields = append(fields, innerField)
 		}
+	}
+	structInfo.Fields = fields
+	return nil
+}
 
-		// Check for newc:"-" tag (backward compatibility)
-		if strings.HasPrefix(part, "newc:") {
-			tagValue := strings.TrimPrefix(part, "newc:")
-			tagValue = strings.Trim(tagValue, `"`)
-			if tagValue == "-" {
-				skip = true
+// collectMethods returns the methods declared on the named type in files,
+// with value or pointer receivers
+func collectMethods(files []*ast.File, typeName string) []MethodInfo {
+	methods := []MethodInfo{}
+	for _, file := range files {
+		generated := isGeneratedFile(file)
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+			if receiverTypeName(funcDecl.Recv.List[0].Type) != typeName {
+				continue
 			}
+			methods = append(methods, MethodInfo{
+				Name:      funcDecl.Name.Name,
+				Params:    fieldListTypes(funcDecl.Type.Params),
+				Results:   fieldListTypes(funcDecl.Type.Results),
+				Generated: generated,
+			})
 		}
+	}
+	return methods
+}
 
-		// Also support gonstructor:"-" tag for compatibility
-		if strings.HasPrefix(part, "gonstructor:") {
-			tagValue := strings.TrimPrefix(part, "gonstructor:")
-			tagValue = strings.Trim(tagValue, `"`)
-			if tagValue == "-" {
-				skip = true
+// collectBuilders returns the builders declared in files that nested builders
+// can use, keyed by the struct they build: a New<X>Builder function without
+// parameters and a Build method without parameters returning X or *X, but no
+// error. Builders of generic structs are left out.
+func collectBuilders(files []*ast.File) map[string]BuilderInfo {
+	constructors := map[string]bool{}
+	builds := map[string]BuilderInfo{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || len(funcDecl.Type.Params.List) > 0 {
+				continue
+			}
+
+			if funcDecl.Recv == nil {
+				name, ok := strings.CutPrefix(funcDecl.Name.Name, "New")
+				if structName, isBuilder := strings.CutSuffix(name, "Builder"); ok && isBuilder && funcDecl.Type.TypeParams == nil {
+					constructors[structName] = true
+				}
+				continue
 			}
+
+			structName, ok := strings.CutSuffix(receiverTypeName(funcDecl.Recv.List[0].Type), "Builder")
+			if !ok || funcDecl.Name.Name != "Build" {
+				continue
+			}
+			if results := fieldListTypes(funcDecl.Type.Results); len(results) == 1 {
+				switch results[0] {
+				case structName:
+					builds[structName] = BuilderInfo{ReturnsValue: true}
+				case "*" + structName:
+					builds[structName] = BuilderInfo{}
+				}
+			}
+		}
+	}
+
+	builders := map[string]BuilderInfo{}
+	for structName, builder := range builds {
+		if constructors[structName] {
+			builders[structName] = builder
+		}
+	}
+	return builders
+}
+
+// receiverTypeName returns the name of the receiver base type, e.g., "Cache"
+// for "*Cache[K, V]"
+func receiverTypeName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// fieldListTypes returns the types of a parameter or result list, one per
+// parameter even if several share a type
+func fieldListTypes(list *ast.FieldList) []string {
+	result := []string{}
+	if list == nil {
+		return result
+	}
+	for _, field := range list.List {
+		fieldType := exprToString(field.Type)
+		for range max(1, len(field.Names)) {
+			result = append(result, fieldType)
+		}
+	}
+	return result
+}
+
+// Method returns the method of the struct with the given name, if known
+func (s *StructInfo) Method(name string) (MethodInfo, bool) {
+	for _, method := range s.Methods {
+		if method.Name == name {
+			return method, true
+		}
+	}
+	return MethodInfo{}, false
+}
+
+// exprToString renders an ast.Expr exactly as it is written in source,
+// including function types, inline struct and interface types and
+// instantiated generic types such as atomic.Pointer[T]
+func exprToString(expr ast.Expr) string {
+	// Without positions, the printer would write the comments of inline struct
+	// fields and interface methods after the type, so they are left out
+	var commented []*ast.Field
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if field, ok := n.(*ast.Field); ok && (field.Doc != nil || field.Comment != nil) {
+			commented = append(commented, field)
 		}
+		return true
+	})
+	comments := make([][2]*ast.CommentGroup, len(commented))
+	for i, field := range commented {
+		comments[i] = [2]*ast.CommentGroup{field.Doc, field.Comment}
+		field.Doc, field.Comment = nil, nil
 	}
+	defer func() {
+		for i, field := range commented {
+			field.Doc, field.Comment = comments[i][0], comments[i][1]
+		}
+	}()
 
-	return skip, skipGetter, skipSetter
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), expr); err != nil {
+		return types.ExprString(expr)
+	}
+	return buf.String()
+}
+
+// parseFieldSkipTags parses field tags to determine skip behavior
+// Returns: (skip, skipGetter, skipSetter)
+// - skip: completely skip this field (constructor:"-" or newc:"-" or gonstructor:"-")
+// - skipGetter: skip getter generation only (constructor:"getter:false")
+// - skipSetter: skip setter/constructor parameter only (constructor:"setter:false")
+// Invalid tags skip nothing; use parseFieldTag to get the error.
+func parseFieldSkipTags(tag string) (bool, bool, bool) {
+	field := FieldInfo{Tag: tag}
+	if err := applyTagOptions(&field); err != nil {
+		return false, false, false
+	}
+	return field.Skip, field.SkipGetter, field.SkipSetter
 }
 
 // shouldSkipField checks if a field should be skipped based on its tag (backward compatibility)
@@ -214,6 +475,15 @@ func (s *StructInfo) GetFieldsForConstructor() []FieldInfo {
 	return result
 }
 
+// Path returns the selector of the field relative to the struct, e.g.,
+// "BaseModel.id" for a field of a flattened embedded struct
+func (f FieldInfo) Path() string {
+	if f.Parent != "" {
+		return f.Parent + "." + f.Name
+	}
+	return f.Name
+}
+
 // GetFieldsForGetter returns fields that should have getters generated
 // Fields with Skip=true or SkipGetter=true are excluded
 func (s *StructInfo) GetFieldsForGetter() []FieldInfo {
@@ -225,3 +495,29 @@ func (s *StructInfo) GetFieldsForGetter() []FieldInfo {
 	}
 	return result
 }
+
+// TypeParamsDecl returns the type parameter declaration list, e.g., "[K comparable, V any]".
+// Returns an empty string for non-generic structs.
+func (s *StructInfo) TypeParamsDecl() string {
+	if len(s.TypeParams) == 0 {
+		return ""
+	}
+	params := make([]string, len(s.TypeParams))
+	for i, param := range s.TypeParams {
+		params[i] = param.Name + " " + param.Constraint
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// TypeArgs returns the type argument list used to instantiate the struct, e.g., "[K, V]".
+// Returns an empty string for non-generic structs.
+func (s *StructInfo) TypeArgs() string {
+	if len(s.TypeParams) == 0 {
+		return ""
+	}
+	names := make([]string, len(s.TypeParams))
+	for i, param := range s.TypeParams {
+		names[i] = param.Name
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
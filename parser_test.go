@@ -3,6 +3,8 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -112,6 +114,9 @@ func TestParseFieldSkipTags(t *testing.T) {
 		{"skip setter", "`constructor:\"setter:false\"`", false, false, true},
 		{"other tag", "`json:\"name\"`", false, false, false},
 		{"mixed tags", "`json:\"name\" constructor:\"getter:false\"`", false, true, false},
+		{"combined options", "`constructor:\"getter:false,setter:false\"`", false, true, true},
+		{"spaced options", "`constructor:\"getter:false, setter:false\"`", false, true, true},
+		{"invalid tag", "`constructor:\"unknown\"`", false, false, false},
 	}
 
 	for _, tt := range tests {
@@ -225,3 +230,612 @@ type ComplexStruct struct {
 		}
 	}
 }
+
+func TestParseGenericStruct(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+type Number interface {
+	~int | ~float64
+}
+
+type Cache[K comparable, V any, N Number] struct {
+	items map[K]V
+	size  N
+	next  *Cache[K, V, N]
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ParseStruct(testFile, "Cache")
+	if err != nil {
+		t.Fatalf("ParseStruct failed: %v", err)
+	}
+
+	expected := []TypeParam{
+		{Name: "K", Constraint: "comparable"},
+		{Name: "V", Constraint: "any"},
+		{Name: "N", Constraint: "Number"},
+	}
+	if len(info.TypeParams) != len(expected) {
+		t.Fatalf("Expected %d type params, got %d", len(expected), len(info.TypeParams))
+	}
+	for i, param := range expected {
+		if info.TypeParams[i] != param {
+			t.Errorf("Type param %d: expected %+v, got %+v", i, param, info.TypeParams[i])
+		}
+	}
+
+	if decl := info.TypeParamsDecl(); decl != "[K comparable, V any, N Number]" {
+		t.Errorf("Unexpected TypeParamsDecl: %s", decl)
+	}
+	if args := info.TypeArgs(); args != "[K, V, N]" {
+		t.Errorf("Unexpected TypeArgs: %s", args)
+	}
+	if info.Fields[2].Type != "*Cache[K, V, N]" {
+		t.Errorf("Expected field type '*Cache[K, V, N]', got '%s'", info.Fields[2].Type)
+	}
+}
+
+func TestExprToStringFullFidelity(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type CallbackStruct[T any] struct {
+	callback  func(ctx context.Context, n int) (string, error)
+	handlers  map[string]func(ctx context.Context) error
+	variadic  func(format string, args ...any)
+	pointer   atomic.Pointer[T]
+	pair      Pair[int, string]
+	paren     (*int)
+	directed  chan<- <-chan int
+	reader    interface{ Read(p []byte) (int, error) }
+	point     struct{ X, Y int }
+	empty     struct{}
+	commented struct {
+		// X is documented
+		X int // trailing
+	}
+	closer interface {
+		Close() error // closes it
+	}
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ParseStruct(testFile, "CallbackStruct")
+	if err != nil {
+		t.Fatalf("ParseStruct failed: %v", err)
+	}
+
+	expectedTypes := map[string]string{
+		"callback":  "func(ctx context.Context, n int) (string, error)",
+		"handlers":  "map[string]func(ctx context.Context) error",
+		"variadic":  "func(format string, args ...any)",
+		"pointer":   "atomic.Pointer[T]",
+		"pair":      "Pair[int, string]",
+		"paren":     "(*int)",
+		"directed":  "chan<- <-chan int",
+		"reader":    "interface{ Read(p []byte) (int, error) }",
+		"point":     "struct{ X, Y int }",
+		"empty":     "struct{}",
+		"commented": "struct{ X int }",
+		"closer":    "interface{ Close() error }",
+	}
+
+	for _, field := range info.Fields {
+		if field.Type != expectedTypes[field.Name] {
+			t.Errorf("Field %s: expected type %s, got %s", field.Name, expectedTypes[field.Name], field.Type)
+		}
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"a.go": `package test
+
+type Zebra struct {
+	name string
+}
+
+type Alias = Zebra
+
+type Apple struct {
+	color string
+}
+`,
+		"b.go": `package test
+
+type Mango struct {
+	weight int
+}
+
+type NotStruct int
+`,
+		"c_test.go": `package test
+
+type TestOnly struct{}
+`,
+		"ignored.go": `//go:build ignore
+
+package test
+
+type Ignored struct{}
+`,
+		"zz_generated.go": `// Code generated by other-tool. DO NOT EDIT.
+
+package test
+
+type Generated struct{}
+`,
+		"mango_gen.go": `package test
+
+// Code generated by constructor. DO NOT EDIT.
+
+type MangoBuilder struct{}
+`,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// All structs, sorted by name, skipping test, constrained and generated files
+	infos, err := ParseDir(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+	names := []string{}
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+	if strings.Join(names, ",") != "Apple,Mango,Zebra" {
+		t.Errorf("Expected Apple,Mango,Zebra, got %v", names)
+	}
+
+	// Named structs, in the order given
+	infos, err = ParseDir(tmpDir, []string{"Zebra", "Mango"})
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+	if len(infos) != 2 || infos[0].Name != "Zebra" || infos[1].Name != "Mango" {
+		t.Errorf("Expected Zebra, Mango in order, got %v", infos)
+	}
+
+	for _, name := range []string{"NotStruct", "Ignored", "TestOnly", "Generated", "MangoBuilder"} {
+		if _, err := ParseDir(tmpDir, []string{name}); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+}
+
+func TestParseDirLookupErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"server.go": `package test
+
+type Server struct {
+	host string
+}
+`,
+		"server_custom.go": `//go:build custom
+
+package test
+
+type Server struct {
+	host string
+}
+`,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := ParseDir(tmpDir, []string{"Sever"})
+	if err == nil || !strings.Contains(err.Error(), "did you mean Server?") {
+		t.Errorf("Expected suggestion for misspelled struct, got %v", err)
+	}
+
+	// Enabling the build tag makes both declarations visible
+	defer func(tags []string) { buildContext.BuildTags = tags }(buildContext.BuildTags)
+	buildContext.BuildTags = []string{"custom"}
+
+	_, err = ParseDir(tmpDir, []string{"Server"})
+	if err == nil || !strings.Contains(err.Error(), "server.go:3") || !strings.Contains(err.Error(), "server_custom.go:5") {
+		t.Errorf("Expected ambiguity error listing both declarations, got %v", err)
+	}
+}
+
+func TestParseDirMethods(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `package test
+
+import "context"
+
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
+
+func (c *Cache[K, V]) open(ctx context.Context) error { return nil }
+
+func (c Cache[K, V]) Len() int { return len(c.items) }
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "cache.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Methods may be declared in another file of the package
+	validate := `package test
+
+func (c *Cache[K, V]) Validate() error { return nil }
+
+func Validate() {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "validate.go"), []byte(validate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := ParseDir(tmpDir, []string{"Cache"})
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+
+	expected := []MethodInfo{
+		{Name: "open", Params: []string{"context.Context"}, Results: []string{"error"}},
+		{Name: "Len", Params: []string{}, Results: []string{"int"}},
+		{Name: "Validate", Params: []string{}, Results: []string{"error"}},
+	}
+	if !reflect.DeepEqual(infos[0].Methods, expected) {
+		t.Errorf("Expected methods %v, got %v", expected, infos[0].Methods)
+	}
+
+	// Methods of generated files are marked as such
+	generated := "// Code generated by constructor. DO NOT EDIT.\n\npackage test\n\nfunc (c *Cache[K, V]) GetItems() map[K]V { return c.items }\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "cache_gen.go"), []byte(generated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	infos, err = ParseDir(tmpDir, []string{"Cache"})
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+	method, ok := infos[0].Method("GetItems")
+	if !ok || !method.Generated {
+		t.Errorf("Expected GetItems to be marked as generated, got %+v", method)
+	}
+	if method, _ := infos[0].Method("Validate"); method.Generated {
+		t.Error("Validate should not be marked as generated")
+	}
+}
+
+func TestParseDirBuilders(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `package test
+
+type Server struct {
+	tls  *TLSConfig
+	pool Pool
+}
+
+type TLSConfig struct {
+	certFile string
+}
+
+type Pool struct {
+	size int
+}
+
+type Store struct{}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "server.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Builders may be generated or written by hand; failing builders cannot be nested
+	generated := `// Code generated by constructor. DO NOT EDIT.
+
+package test
+
+type TLSConfigBuilder struct{}
+
+func NewTLSConfigBuilder() *TLSConfigBuilder { return &TLSConfigBuilder{} }
+
+func (b *TLSConfigBuilder) Build() *TLSConfig { return &TLSConfig{} }
+
+type StoreBuilder struct{}
+
+func NewStoreBuilder() *StoreBuilder { return &StoreBuilder{} }
+
+func (b *StoreBuilder) Build() (*Store, error) { return &Store{}, nil }
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "tlsconfig_gen.go"), []byte(generated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manual := `package test
+
+type PoolBuilder struct{}
+
+func NewPoolBuilder() *PoolBuilder { return &PoolBuilder{} }
+
+func (b PoolBuilder) Build() Pool { return Pool{} }
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "pool.go"), []byte(manual), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := ParseDir(tmpDir, []string{"Server"})
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+
+	expected := map[string]BuilderInfo{
+		"TLSConfig": {},
+		"Pool":      {ReturnsValue: true},
+	}
+	if !reflect.DeepEqual(infos[0].Builders, expected) {
+		t.Errorf("Expected builders %v, got %v", expected, infos[0].Builders)
+	}
+}
+
+func TestParseEmbeddedFields(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+import "sync"
+
+type Model struct {
+	*sync.Mutex
+	BaseModel ` + "`constructor:\"flatten\"`" + `
+	audit
+	name string
+}
+
+type BaseModel struct {
+	ID      int64
+	version int
+}
+
+type audit struct {
+	author string
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ParseStruct(testFile, "Model")
+	if err != nil {
+		t.Fatalf("ParseStruct failed: %v", err)
+	}
+
+	// Embedded fields are named after their type, flattened fields follow their struct
+	expected := []struct {
+		name     string
+		parent   string
+		exported bool
+		embedded bool
+	}{
+		{"Mutex", "", true, true},
+		{"BaseModel", "", true, true},
+		{"ID", "BaseModel", true, false},
+		{"version", "BaseModel", false, false},
+		{"audit", "", false, true},
+		{"name", "", false, false},
+	}
+	if len(info.Fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got %d", len(expected), len(info.Fields))
+	}
+	for i, want := range expected {
+		field := info.Fields[i]
+		if field.Name != want.name || field.Parent != want.parent || field.Exported != want.exported || field.Embedded != want.embedded {
+			t.Errorf("Field %d: expected %+v, got name=%s parent=%s exported=%v embedded=%v",
+				i, want, field.Name, field.Parent, field.Exported, field.Embedded)
+		}
+	}
+	if !info.Fields[1].Flatten || !info.Fields[1].Skip {
+		t.Error("Expected the flattened embedded field to be skipped")
+	}
+	if path := info.Fields[3].Path(); path != "BaseModel.version" {
+		t.Errorf("Expected path 'BaseModel.version', got '%s'", path)
+	}
+
+	// The embedded struct may be declared in another file of the package
+	if err := os.WriteFile(testFile, []byte(strings.Replace(content, "type BaseModel struct", "type baseModelMoved struct", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := "package test\n\ntype BaseModel struct {\n\tID int64\n}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "base.go"), []byte(other), 0644); err != nil {
+		t.Fatal(err)
+	}
+	infos, err := ParseDir(tmpDir, []string{"Model"})
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+	if field := infos[0].Fields[2]; field.Name != "ID" || field.Parent != "BaseModel" {
+		t.Errorf("Expected flattened field ID, got %+v", field)
+	}
+}
+
+func TestParseFlattenErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expectErr string
+	}{
+		{
+			name:      "not embedded",
+			content:   "type Model struct {\n\tbase Base `constructor:\"flatten\"`\n}\n\ntype Base struct {\n\tid int\n}\n",
+			expectErr: `"flatten" requires an embedded struct`,
+		},
+		{
+			name:      "pointer",
+			content:   "type Model struct {\n\t*Base `constructor:\"flatten\"`\n}\n\ntype Base struct {\n\tid int\n}\n",
+			expectErr: "embedded by value",
+		},
+		{
+			name:      "unknown struct",
+			content:   "type Model struct {\n\tBase `constructor:\"flatten\"`\n}\n",
+			expectErr: "struct Base not found",
+		},
+		{
+			name:      "field clash",
+			content:   "type Model struct {\n\tBase `constructor:\"flatten\"`\n\tid int\n}\n\ntype Base struct {\n\tid int\n}\n",
+			expectErr: "field id of flattened struct Base clashes with field id of Model",
+		},
+		{
+			name:      "nested flatten",
+			content:   "type Model struct {\n\tBase `constructor:\"flatten\"`\n}\n\ntype Base struct {\n\tInner `constructor:\"flatten\"`\n}\n\ntype Inner struct {\n\tid int\n}\n",
+			expectErr: "flattens Inner in turn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "model.go"), []byte("package test\n\n"+tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ParseDir(tmpDir, []string{"Model"})
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestParseFieldDocs(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+// Server serves HTTP requests.
+//
+//constructor:gen builder
+type Server struct {
+	// Host name to bind to.
+	//
+	// Empty binds all interfaces.
+	host string // Ignored in favor of the doc comment
+
+	port int // TCP port to listen on
+
+	timeout int
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ParseStruct(testFile, "Server")
+	if err != nil {
+		t.Fatalf("ParseStruct failed: %v", err)
+	}
+
+	// The directive is left out of the struct doc
+	if info.Doc != "Server serves HTTP requests." {
+		t.Errorf("Expected struct doc %q, got %q", "Server serves HTTP requests.", info.Doc)
+	}
+
+	expected := []string{"Host name to bind to.\n\nEmpty binds all interfaces.", "TCP port to listen on", ""}
+	for i, want := range expected {
+		if info.Fields[i].Doc != want {
+			t.Errorf("Field %s: expected doc %q, got %q", info.Fields[i].Name, want, info.Fields[i].Doc)
+		}
+	}
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		expect string
+	}{
+		{"no doc", "", ""},
+		{"line comment", "Deprecated: use timeout instead.", "use timeout instead."},
+		{"paragraph", "Retry delay.\n\nDeprecated: retries are\nconfigured by policy.", "retries are configured by policy."},
+		{"not a paragraph", "Retry delay.\nDeprecated: use policy.", ""},
+		{"lowercase", "deprecated: use policy.", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if notice := deprecationNotice(tt.doc); notice != tt.expect {
+				t.Errorf("deprecationNotice(%q) = %q, want %q", tt.doc, notice, tt.expect)
+			}
+		})
+	}
+}
+
+func TestParseStructDirective(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+// Annotated is configured by its directive
+//
+//constructor:gen builder init=setup
+type Annotated struct {
+	name string
+}
+
+type (
+	// Grouped is declared in a group
+	//constructor:gen options
+	Grouped struct {
+		name string
+	}
+
+	Plain struct {
+		name string
+	}
+)
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := ParseDir(tmpDir, []string{"Annotated", "Grouped", "Plain"})
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+
+	expected := []struct {
+		annotated bool
+		directive string
+	}{
+		{true, "builder init=setup"},
+		{true, "options"},
+		{false, ""},
+	}
+	for i, want := range expected {
+		if infos[i].Annotated != want.annotated || infos[i].Directive != want.directive {
+			t.Errorf("%s: expected (%v, %q), got (%v, %q)", infos[i].Name, want.annotated, want.directive, infos[i].Annotated, infos[i].Directive)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+)
+
+// resolvePresence returns the field tagged constructor:"presence" holding the
+// presence bitmask of the struct, or nil if presence is not tracked. fields
+// are the fields tracked, one bit each. Returns an error if the bitmask field
+// is unusable or too narrow, or if the generated methods would clash with
+// fields, declared methods or generated getters.
+func (g *Generator) resolvePresence(fields []FieldInfo) (*FieldInfo, error) {
+	var presence *FieldInfo
+	for i, field := range g.info.Fields {
+		if !field.Presence {
+			continue
+		}
+		if presence != nil {
+			return nil, fmt.Errorf("%s: field %s is the second presence field of %s, after %s", field.Pos, field.Name, g.info.Name, presence.Name)
+		}
+		presence = &g.info.Fields[i]
+	}
+	if presence == nil {
+		return nil, nil
+	}
+
+	if presence.Exported {
+		return nil, fmt.Errorf("%s: presence field %s must be unexported", presence.Pos, presence.Name)
+	}
+	bits := presenceBits(*presence)
+	if bits == 0 {
+		return nil, fmt.Errorf("%s: presence field %s must be of type uint8, uint16, uint32 or uint64, got %s", presence.Pos, presence.Name, presence.Type)
+	}
+	if len(fields) > bits {
+		return nil, fmt.Errorf("%s: presence field %s has %d bits for %d fields", presence.Pos, presence.Name, bits, len(fields))
+	}
+
+	taken := map[string]string{}
+	for _, field := range g.info.Fields {
+		taken[field.Name] = "field " + field.Name
+	}
+	for _, method := range g.info.Methods {
+		if !method.Generated {
+			taken[method.Name] = "method " + method.Name
+		}
+	}
+	if g.config.WithGetter {
+		for _, field := range g.info.GetFieldsForGetter() {
+			if !field.Exported {
+				taken[g.getterName(field)] = "the getter of field " + field.Name
+			}
+		}
+	}
+	if other, ok := taken["IsSet"]; ok {
+		return nil, fmt.Errorf("%s: method IsSet of presence field %s clashes with %s of %s", presence.Pos, presence.Name, other, g.info.Name)
+	}
+	for _, field := range fields {
+		if other, ok := taken[hasMethodName(field)]; ok {
+			return nil, fmt.Errorf("%s: method %s of field %s clashes with %s of %s", field.Pos, hasMethodName(field), field.Name, other, g.info.Name)
+		}
+	}
+	return presence, nil
+}
+
+// presenceBits returns the number of bits of the presence field, or 0 if its
+// type is not a sized unsigned integer
+func presenceBits(field FieldInfo) int {
+	t := field.TypeInfo
+	if t == nil {
+		obj, ok := types.Universe.Lookup(field.Type).(*types.TypeName)
+		if !ok {
+			return 0
+		}
+		t = obj.Type()
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return 0
+	}
+	switch basic.Kind() {
+	case types.Uint8:
+		return 8
+	case types.Uint16:
+		return 16
+	case types.Uint32:
+		return 32
+	case types.Uint64:
+		return 64
+	}
+	return 0
+}
+
+// presenceBit returns the name of the constant holding the presence bit of
+// field, e.g., "serverTimeoutPresent"
+func (g *Generator) presenceBit(field FieldInfo) string {
+	return toLowerCamelCase(g.info.Name) + toUpperCamelCase(field.Name) + "Present"
+}
+
+// hasMethodName returns the name of the method reporting whether field was set, e.g., "HasTimeout"
+func hasMethodName(field FieldInfo) string {
+	return "Has" + toUpperCamelCase(field.APIName())
+}
+
+// presenceMark returns the statement recording in the presence field of
+// target that field was set, at the given indentation, or an empty string if
+// presence is not tracked. Builders name their copy of the bitmask like the
+// struct, so target may be a builder as well.
+func (g *Generator) presenceMark(indent, target string, field FieldInfo) string {
+	if g.presence == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s%s.%s |= %s\n", indent, target, g.presence.Name, g.presenceBit(field))
+}
+
+// generatePresence generates the presence bits of the tracked fields, a
+// Has<Field> method per field and IsSet looking fields up by name
+func (g *Generator) generatePresence(fields []FieldInfo) string {
+	var buf bytes.Buffer
+
+	buf.WriteString(fmt.Sprintf("// Presence bits of the %s fields, recorded in %s when a field is set explicitly\n", g.info.Name, g.presence.Name))
+	buf.WriteString("const (\n")
+	for i, field := range fields {
+		if i == 0 {
+			buf.WriteString(fmt.Sprintf("\t%s %s = 1 << iota\n", g.presenceBit(field), g.presence.Type))
+		} else {
+			buf.WriteString(fmt.Sprintf("\t%s\n", g.presenceBit(field)))
+		}
+	}
+	buf.WriteString(")\n\n")
+
+	receiverName := g.receiverName()
+	receiverType := "*" + g.typeName()
+	if g.config.Receiver == "value" {
+		receiverType = g.typeName()
+	}
+
+	for _, field := range fields {
+		buf.WriteString(fmt.Sprintf("// %s reports whether the %s field was set explicitly, even to its zero value\n", hasMethodName(field), field.Name))
+		buf.WriteString(deprecatedComment(field, ""))
+		buf.WriteString(fmt.Sprintf("func (%s %s) %s() bool {\n", receiverName, receiverType, hasMethodName(field)))
+		buf.WriteString(fmt.Sprintf("\treturn %s.%s&%s != 0\n", receiverName, g.presence.Name, g.presenceBit(field)))
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString("// IsSet reports whether the field with the given name was set explicitly.\n")
+	buf.WriteString("// Unknown names are reported as not set.\n")
+	buf.WriteString(fmt.Sprintf("func (%s %s) IsSet(field string) bool {\n", receiverName, receiverType))
+	buf.WriteString("\tswitch field {\n")
+	for _, field := range fields {
+		buf.WriteString(fmt.Sprintf("\tcase %q:\n", field.Name))
+		buf.WriteString(fmt.Sprintf("\t\treturn %s.%s()\n", receiverName, hasMethodName(field)))
+	}
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn false\n")
+	buf.WriteString("}\n\n")
+
+	return buf.String()
+}
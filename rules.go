@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validateMatchRule checks that the value of a match=... rule is a valid regular expression
+func validateMatchRule(value string) error {
+	_, err := regexp.Compile(value)
+	return err
+}
+
+// validateOneOfRule checks that a oneof=... rule lists no empty alternatives
+func validateOneOfRule(value string) error {
+	for _, alternative := range strings.Split(value, "|") {
+		if alternative == "" {
+			return fmt.Errorf("empty alternative in %q", value)
+		}
+	}
+	return nil
+}
+
+// ruleViolation returns the condition under which the value expr of field
+// violates rule, e.g., "v.port < 1" for min=1. pattern is the name of the
+// variable holding the compiled regular expression of a match rule.
+func ruleViolation(field FieldInfo, rule TagOption, expr, pattern string) (string, error) {
+	kind, qualifier := classifyType(field)
+	invalid := func(format string, args ...any) (string, error) {
+		return "", fmt.Errorf("%s: field %s: rule %s: %s", field.Pos, field.Name, ruleString(rule), fmt.Sprintf(format, args...))
+	}
+	notApplicable := func() (string, error) {
+		return invalid("does not apply to type %s", field.Type)
+	}
+
+	switch rule.Key {
+	case "min", "max":
+		operator := "<"
+		if rule.Key == "max" {
+			operator = ">"
+		}
+		switch kind {
+		case kindString, kindCollection:
+			n, err := strconv.ParseUint(rule.Value, 10, 0)
+			if err != nil {
+				return invalid("length must be a non-negative integer")
+			}
+			return fmt.Sprintf("len(%s) %s %d", expr, operator, n), nil
+		case kindDuration:
+			d, err := time.ParseDuration(rule.Value)
+			if err != nil {
+				return invalid("%v", err)
+			}
+			return fmt.Sprintf("%s %s %s", expr, operator, durationExpr(d, qualifier)), nil
+		case kindInt, kindUint, kindFloat:
+			if err := checkNumber(kind, rule.Value); err != nil {
+				return invalid("%v", err)
+			}
+			return fmt.Sprintf("%s %s %s", expr, operator, rule.Value), nil
+		}
+		return notApplicable()
+
+	case "nonempty":
+		if kind != kindString && kind != kindCollection {
+			return notApplicable()
+		}
+		return fmt.Sprintf("len(%s) == 0", expr), nil
+
+	case "match":
+		if kind != kindString {
+			return notApplicable()
+		}
+		if field.Type != "string" {
+			expr = "string(" + expr + ")"
+		}
+		return fmt.Sprintf("!%s.MatchString(%s)", pattern, expr), nil
+
+	case "oneof":
+		alternatives := strings.Split(rule.Value, "|")
+		conditions := make([]string, len(alternatives))
+		for i, alternative := range alternatives {
+			switch kind {
+			case kindString:
+				alternative = strconv.Quote(alternative)
+			case kindInt, kindUint, kindFloat:
+				if err := checkNumber(kind, alternative); err != nil {
+					return invalid("%v", err)
+				}
+			default:
+				return notApplicable()
+			}
+			conditions[i] = fmt.Sprintf("%s != %s", expr, alternative)
+		}
+		return strings.Join(conditions, " && "), nil
+
+	case "notnil":
+		if kind != kindNilable && kind != kindCollection {
+			return notApplicable()
+		}
+		return expr + " == nil", nil
+	}
+
+	return invalid("unknown rule")
+}
+
+// checkNumber checks that value is a number literal of the given kind, which
+// is used as written in the generated checks
+func checkNumber(kind valueKind, value string) error {
+	var err error
+	switch kind {
+	case kindInt:
+		_, err = strconv.ParseInt(value, 0, 64)
+	case kindUint:
+		_, err = strconv.ParseUint(value, 0, 64)
+	default:
+		_, err = strconv.ParseFloat(value, 64)
+	}
+	if err != nil {
+		return err
+	}
+	return checkNumberLiteral(value)
+}
+
+// ruleString returns a rule as written in the tag, e.g., "max=65535"
+func ruleString(rule TagOption) string {
+	if !tagOptionSpecs[rule.Key].hasValue {
+		return rule.Key
+	}
+	return rule.Key + "=" + rule.Value
+}
+
+// hasFieldRules reports whether any field of the struct declares validation rules
+func (g *Generator) hasFieldRules() bool {
+	for _, field := range g.info.Fields {
+		if !field.Skip && len(field.Rules()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validationErrorName returns the name of the error type reporting rule violations
+func (g *Generator) validationErrorName() string {
+	return g.info.Name + "ValidationError"
+}
+
+// ruleFuncName returns the name of the function checking the field rules
+func (g *Generator) ruleFuncName() string {
+	return "validate" + g.info.Name + "Fields"
+}
+
+// patternName returns the name of the variable holding the compiled regular
+// expression of the match rule of field
+func (g *Generator) patternName(field FieldInfo) string {
+	return toLowerCamelCase(g.info.Name) + toUpperCamelCase(field.Name) + "Pattern"
+}
+
+// fieldRuleChecks generates the statements returning a validation error if the
+// value expr of field violates one of its rules
+func (g *Generator) fieldRuleChecks(field FieldInfo, expr string) (string, error) {
+	var buf bytes.Buffer
+	for _, rule := range field.Rules() {
+		condition, err := ruleViolation(field, rule, expr, g.patternName(field))
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(fmt.Sprintf("\tif %s {\n", condition))
+		buf.WriteString(fmt.Sprintf("\t\treturn &%s{Field: %q, Rule: %q}\n", g.validationErrorName(), field.APIName(), ruleString(rule)))
+		buf.WriteString("\t}\n")
+	}
+	return buf.String(), nil
+}
+
+// generateFieldRules generates the error type and the function checking the
+// validation rules declared in the field tags, called by every constructor.
+// Returns an empty string if no field declares rules.
+func (g *Generator) generateFieldRules() (string, error) {
+	if !g.hasFieldRules() {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	errorName := g.validationErrorName()
+	typeParams := g.info.TypeParamsDecl()
+
+	// Generate error type
+	buf.WriteString(fmt.Sprintf("// %s reports a field of %s violating a rule of its constructor tag\n", errorName, g.info.Name))
+	buf.WriteString(fmt.Sprintf("type %s struct {\n", errorName))
+	buf.WriteString("\tField string // Field name\n")
+	buf.WriteString("\tRule  string // Violated rule, as written in the tag\n")
+	buf.WriteString("}\n\n")
+	buf.WriteString("// Error implements the error interface\n")
+	buf.WriteString(fmt.Sprintf("func (e *%s) Error() string {\n", errorName))
+	buf.WriteString(fmt.Sprintf("\treturn fmt.Sprintf(\"invalid %s: field %%s violates rule %%s\", e.Field, e.Rule)\n", g.info.Name))
+	buf.WriteString("}\n\n")
+
+	// Generate check function, with the regular expressions compiled once
+	var patterns, checks bytes.Buffer
+	for _, field := range g.info.Fields {
+		if field.Skip {
+			continue
+		}
+		if value, ok := field.Option("match"); ok {
+			literal := strconv.Quote(value)
+			if !strings.Contains(value, "`") {
+				literal = "`" + value + "`"
+			}
+			patterns.WriteString(fmt.Sprintf("var %s = regexp.MustCompile(%s)\n\n", g.patternName(field), literal))
+		}
+
+		code, err := g.fieldRuleChecks(field, "v."+field.Path())
+		if err != nil {
+			return "", err
+		}
+		checks.WriteString(code)
+	}
+
+	buf.Write(patterns.Bytes())
+	buf.WriteString(fmt.Sprintf("// %s checks the fields of v against the rules of their constructor tags\n", g.ruleFuncName()))
+	buf.WriteString(fmt.Sprintf("func %s%s(v *%s) error {\n", g.ruleFuncName(), typeParams, g.typeName()))
+	buf.Write(checks.Bytes())
+	buf.WriteString("\treturn nil\n")
+	buf.WriteString("}\n")
+
+	return buf.String(), nil
+}
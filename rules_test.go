@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleViolation(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		rule      TagOption
+		expect    string
+	}{
+		{"int", TagOption{Key: "min", Value: "1"}, "v.f < 1"},
+		{"uint16", TagOption{Key: "max", Value: "0xffff"}, "v.f > 0xffff"},
+		{"float64", TagOption{Key: "max", Value: "0.5"}, "v.f > 0.5"},
+		{"time.Duration", TagOption{Key: "min", Value: "100ms"}, "v.f < 100 * time.Millisecond"},
+		{"string", TagOption{Key: "max", Value: "64"}, "len(v.f) > 64"},
+		{"[]string", TagOption{Key: "min", Value: "1"}, "len(v.f) < 1"},
+		{"string", TagOption{Key: "nonempty"}, "len(v.f) == 0"},
+		{"map[string]int", TagOption{Key: "nonempty"}, "len(v.f) == 0"},
+		{"string", TagOption{Key: "match", Value: "^[a-z]+$"}, "!pattern.MatchString(v.f)"},
+		{"string", TagOption{Key: "oneof", Value: "dev|prod"}, `v.f != "dev" && v.f != "prod"`},
+		{"int", TagOption{Key: "oneof", Value: "1|2|3"}, "v.f != 1 && v.f != 2 && v.f != 3"},
+		{"*Logger", TagOption{Key: "notnil"}, "v.f == nil"},
+		{"func()", TagOption{Key: "notnil"}, "v.f == nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldType+" "+ruleString(tt.rule), func(t *testing.T) {
+			got, err := ruleViolation(FieldInfo{Name: "f", Type: tt.fieldType}, tt.rule, "v.f", "pattern")
+			if err != nil {
+				t.Fatalf("ruleViolation failed: %v", err)
+			}
+			if got != tt.expect {
+				t.Errorf("ruleViolation() = %q, want %q", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestRuleViolationErrors(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		rule      TagOption
+		expectErr string
+	}{
+		{"bool", TagOption{Key: "min", Value: "1"}, "does not apply to type bool"},
+		{"int", TagOption{Key: "min", Value: "one"}, "invalid syntax"},
+		{"string", TagOption{Key: "max", Value: "-1"}, "non-negative integer"},
+		{"int", TagOption{Key: "nonempty"}, "does not apply to type int"},
+		{"[]byte", TagOption{Key: "match", Value: "^a"}, "does not apply to type []byte"},
+		{"int", TagOption{Key: "oneof", Value: "1|two"}, "invalid syntax"},
+		{"float64", TagOption{Key: "max", Value: "Inf"}, `"Inf" is not a Go number literal`},
+		{"float64", TagOption{Key: "oneof", Value: "1|NaN"}, `"NaN" is not a Go number literal`},
+		{"string", TagOption{Key: "notnil"}, "does not apply to type string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldType+" "+ruleString(tt.rule), func(t *testing.T) {
+			_, err := ruleViolation(FieldInfo{Name: "f", Type: tt.fieldType, Pos: "test.go:3"}, tt.rule, "v.f", "pattern")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.HasPrefix(err.Error(), "test.go:3: field f: rule "+ruleString(tt.rule)) || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// buildContext selects the files of a package according to build constraints.
+// Custom build tags are added with the -tags flag.
+var buildContext = build.Default
+
+// structDecl is a struct declaration found in a package
+type structDecl struct {
+	file *ast.File
+	spec *ast.TypeSpec
+	pos  string // Source position, e.g., "user.go:12"
+}
+
+// packageSourceFiles returns the paths of the non-test Go files in dir that
+// match the build constraints of buildContext, sorted by name.
+// A directory without Go files yields no files and no error.
+func packageSourceFiles(dir string) ([]string, error) {
+	bp, err := buildContext.ImportDir(dir, 0)
+	if err != nil {
+		var noGoErr *build.NoGoError
+		if errors.As(err, &noGoErr) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load package in %s: %w", dir, err)
+	}
+
+	files := []string{}
+	for _, name := range append(bp.GoFiles, bp.CgoFiles...) {
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseSourceDir parses the source files of the package in dir, including generated files
+func parseSourceDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	filenames, err := packageSourceFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*ast.File, 0, len(filenames))
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse file: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// isGeneratedFile reports whether file was generated, either by this tool or
+// by any other tool following the "Code generated ... DO NOT EDIT." convention
+func isGeneratedFile(file *ast.File) bool {
+	if ast.IsGenerated(file) {
+		return true
+	}
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if comment.Text == generatedHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findStructDecls returns the top-level struct declarations of the
+// non-generated files, grouped by struct name
+func findStructDecls(fset *token.FileSet, files []*ast.File) map[string][]structDecl {
+	decls := map[string][]structDecl{}
+	for _, file := range files {
+		if isGeneratedFile(file) {
+			continue
+		}
+		for _, typeSpec := range structSpecs(file) {
+			position := fset.Position(typeSpec.Pos())
+			decls[typeSpec.Name.Name] = append(decls[typeSpec.Name.Name], structDecl{
+				file: file,
+				spec: typeSpec,
+				pos:  fmt.Sprintf("%s:%d", position.Filename, position.Line),
+			})
+		}
+	}
+	return decls
+}
+
+// selectStructDecls picks the named struct declarations in the order given, or
+// all of them sorted by name when structNames is empty. Unknown names are
+// reported with close matches, names declared more than once with every position.
+func selectStructDecls(decls map[string][]structDecl, structNames []string, dir string) ([]structDecl, error) {
+	available := make([]string, 0, len(decls))
+	for name := range decls {
+		available = append(available, name)
+	}
+	sort.Strings(available)
+
+	if len(structNames) == 0 {
+		structNames = available
+	}
+
+	result := make([]structDecl, 0, len(structNames))
+	for _, name := range structNames {
+		candidates := decls[name]
+		switch len(candidates) {
+		case 0:
+			return nil, notFoundError(name, dir, available)
+		case 1:
+			result = append(result, candidates[0])
+		default:
+			positions := make([]string, len(candidates))
+			for i, decl := range candidates {
+				positions[i] = decl.pos
+			}
+			return nil, fmt.Errorf("struct %s is ambiguous, it is declared in %s", name, strings.Join(positions, ", "))
+		}
+	}
+	return result, nil
+}
+
+// notFoundError describes a missing struct, suggesting similarly named structs
+func notFoundError(name, dir string, available []string) error {
+	if len(available) == 0 {
+		return fmt.Errorf("struct %s not found: no structs declared in package %s", name, dir)
+	}
+
+	suggestions := []string{}
+	for _, candidate := range available {
+		if isSimilarName(name, candidate) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	msg := fmt.Sprintf("struct %s not found in package %s", name, dir)
+	if len(suggestions) > 0 {
+		msg += fmt.Sprintf("; did you mean %s?", strings.Join(suggestions, " or "))
+	}
+	msg += fmt.Sprintf(" (available: %s)", strings.Join(available, ", "))
+	return errors.New(msg)
+}
+
+// isSimilarName reports whether candidate looks like a misspelling of name
+func isSimilarName(name, candidate string) bool {
+	if strings.EqualFold(name, candidate) {
+		return true
+	}
+	maxDistance := max(1, len(name)/4)
+	return levenshtein(strings.ToLower(name), strings.ToLower(candidate)) <= maxDistance
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
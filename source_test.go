@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"server", "server", 0},
+		{"server", "sever", 1},
+		{"server", "servers", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestNotFoundError(t *testing.T) {
+	available := []string{"Client", "Server", "ServerConfig"}
+
+	tests := []struct {
+		name     string
+		contains []string
+		excludes []string
+	}{
+		{"server", []string{"did you mean Server?", "available: Client, Server, ServerConfig"}, nil},
+		{"Servr", []string{"did you mean Server?"}, []string{"ServerConfig?"}},
+		{"Database", []string{"available: Client, Server, ServerConfig"}, []string{"did you mean"}},
+	}
+
+	for _, tt := range tests {
+		msg := notFoundError(tt.name, ".", available).Error()
+		for _, want := range tt.contains {
+			if !strings.Contains(msg, want) {
+				t.Errorf("notFoundError(%q) = %q, should contain %q", tt.name, msg, want)
+			}
+		}
+		for _, unwanted := range tt.excludes {
+			if strings.Contains(msg, unwanted) {
+				t.Errorf("notFoundError(%q) = %q, should not contain %q", tt.name, msg, unwanted)
+			}
+		}
+	}
+
+	if msg := notFoundError("Server", ".", nil).Error(); !strings.Contains(msg, "no structs declared") {
+		t.Errorf("Expected message about empty package, got %q", msg)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// generateStepBuilderConstructor generates a step builder: one interface per
+// required field, in declaration order, each returning the step of the next
+// required field, followed by a final step setting the optional fields and
+// building the struct. Leaving out a required field is thus a compile error.
+func (g *Generator) generateStepBuilderConstructor(fields []FieldInfo) (string, error) {
+	var buf bytes.Buffer
+
+	defaults, err := g.fieldDefaults()
+	if err != nil {
+		return "", err
+	}
+
+	typeParams := g.info.TypeParamsDecl()
+	typeArgs := g.info.TypeArgs()
+	implName := toLowerCamelCase(g.info.Name) + "StepBuilder"
+	implType := implName + typeArgs
+	buildStep := g.info.Name + "BuildStep"
+	prefix := g.config.SetterPrefix
+
+	required := requiredFields(fields)
+	optional := []FieldInfo{}
+	for _, field := range fields {
+		if !field.Required {
+			optional = append(optional, field)
+		}
+	}
+
+	// The step of each required field, followed by the build step
+	steps := make([]string, 0, len(required)+1)
+	for _, field := range required {
+		step := g.info.Name + toUpperCamelCase(field.APIName()) + "Step"
+		if step == buildStep {
+			return "", fmt.Errorf("%s: field %s clashes with the step %s of the step builder", field.Pos, field.Name, buildStep)
+		}
+		steps = append(steps, step)
+	}
+	steps = append(steps, buildStep)
+
+	returnType := "*" + g.typeName()
+	if g.config.ReturnValue {
+		returnType = g.typeName()
+	}
+	conditions := g.errorConditions("")
+	buildSignature := fmt.Sprintf("Build(%s) %s", g.contextParam(), returnType)
+	if conditions != "" {
+		buildSignature = fmt.Sprintf("Build(%s) (%s, error)", g.contextParam(), returnType)
+	}
+
+	// Generate step interfaces
+	for i, field := range required {
+		methodName := prefix + toUpperCamelCase(field.APIName())
+		paramName := toLowerCamelCase(field.APIName())
+
+		buf.WriteString(fmt.Sprintf("// %s is the step of the %s builder setting the required %s field\n", steps[i], g.info.Name, field.Name))
+		buf.WriteString(fmt.Sprintf("type %s%s interface {\n", steps[i], typeParams))
+		buf.WriteString(fmt.Sprintf("\t// %s sets the %s field\n", methodName, field.Name))
+		buf.WriteString(docComment(field, "\t"))
+		buf.WriteString(fmt.Sprintf("\t%s(%s %s) %s\n", methodName, paramName, field.Type, steps[i+1]+typeArgs))
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString(fmt.Sprintf("// %s is the last step of the %s builder, setting the optional fields\n", buildStep, g.info.Name))
+	buf.WriteString(fmt.Sprintf("type %s%s interface {\n", buildStep, typeParams))
+	for _, field := range optional {
+		methodName := prefix + toUpperCamelCase(field.APIName())
+		paramName := toLowerCamelCase(field.APIName())
+		buf.WriteString(fmt.Sprintf("\t// %s sets the %s field\n", methodName, field.Name))
+		buf.WriteString(docComment(field, "\t"))
+		buf.WriteString(fmt.Sprintf("\t%s(%s %s) %s\n", methodName, paramName, field.Type, buildStep+typeArgs))
+	}
+	if conditions != "" {
+		buf.WriteString(fmt.Sprintf("\t// Build builds the %s, returning an error if %s\n", g.info.Name, conditions))
+	} else {
+		buf.WriteString(fmt.Sprintf("\t// Build builds the %s\n", g.info.Name))
+	}
+	buf.WriteString(fmt.Sprintf("\t%s\n", buildSignature))
+	buf.WriteString("}\n\n")
+
+	// Generate the builder implementing all steps
+	buf.WriteString(fmt.Sprintf("// %s implements the steps of the %s builder\n", implName, g.info.Name))
+	buf.WriteString(fmt.Sprintf("type %s%s struct {\n", implName, typeParams))
+	for _, field := range fields {
+		buf.WriteString(fmt.Sprintf("\t%s %s\n", toLowerCamelCase(field.Name), field.Type))
+	}
+	if g.presence != nil {
+		buf.WriteString(fmt.Sprintf("\t%s %s\n", g.presence.Name, g.presence.Type))
+	}
+	buf.WriteString("}\n\n")
+
+	// Generate builder constructor, starting from the field defaults
+	initialValues := []string{}
+	for _, field := range fields {
+		if expr, ok := defaults[field.Name]; ok {
+			initialValues = append(initialValues, fmt.Sprintf("%s: %s,", toLowerCamelCase(field.Name), expr))
+		}
+	}
+	buf.WriteString(fmt.Sprintf("// New%sBuilder creates a new step builder for %s, starting with the first required field\n", g.info.Name, g.info.Name))
+	buf.WriteString(g.structDocComment())
+	buf.WriteString(fmt.Sprintf("func New%sBuilder%s() %s {\n", g.info.Name, typeParams, steps[0]+typeArgs))
+	buf.WriteString(fmt.Sprintf("\treturn &%s{%s}\n", implType, compositeFields(initialValues, 1)))
+	buf.WriteString("}\n\n")
+
+	// Generate setter methods, required fields advancing to the next step
+	for _, field := range fields {
+		methodName := prefix + toUpperCamelCase(field.APIName())
+		paramName := toLowerCamelCase(field.APIName())
+		next := buildStep
+		for i, r := range required {
+			if r.Name == field.Name {
+				next = steps[i+1]
+			}
+		}
+
+		buf.WriteString(fmt.Sprintf("// %s sets the %s field\n", methodName, field.Name))
+		buf.WriteString(fmt.Sprintf("func (b *%s) %s(%s %s) %s {\n", implType, methodName, paramName, field.Type, next+typeArgs))
+		buf.WriteString(fmt.Sprintf("\tb.%s = %s\n", toLowerCamelCase(field.Name), paramName))
+		buf.WriteString(g.presenceMark("\t", "b", field))
+		buf.WriteString("\treturn b\n")
+		buf.WriteString("}\n\n")
+	}
+
+	// Generate Build method
+	if conditions != "" {
+		buf.WriteString(fmt.Sprintf("// Build builds the %s, returning an error if %s\n", g.info.Name, conditions))
+	} else {
+		buf.WriteString(fmt.Sprintf("// Build builds the %s\n", g.info.Name))
+	}
+	buf.WriteString(fmt.Sprintf("func (b *%s) %s {\n", implType, buildSignature))
+
+	values := []fieldValue{}
+	for _, field := range fields {
+		values = append(values, fieldValue{field, "b." + toLowerCamelCase(field.Name)})
+	}
+	if g.presence != nil {
+		values = append(values, fieldValue{*g.presence, "b." + g.presence.Name})
+	}
+	for _, field := range g.info.Fields {
+		if expr, ok := defaults[field.Name]; ok && field.SkipSetter {
+			values = append(values, fieldValue{field, expr})
+		}
+	}
+	if g.config.ReturnValue {
+		buf.WriteString(fmt.Sprintf("\tv := %s{%s}\n", g.typeName(), compositeFields(g.literalElements(values), 1)))
+	} else {
+		buf.WriteString(fmt.Sprintf("\tv := &%s{%s}\n", g.typeName(), compositeFields(g.literalElements(values), 1)))
+	}
+
+	// Handle init and validate methods
+	buf.WriteString(g.hooks.calls(g.valuePointer(), g.zeroResult()))
+
+	if conditions != "" {
+		buf.WriteString("\treturn v, nil\n")
+	} else {
+		buf.WriteString("\treturn v\n")
+	}
+	buf.WriteString("}\n")
+
+	return buf.String(), nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tagOptionSpec describes a recognized option of the constructor struct tag
+type tagOptionSpec struct {
+	hasValue bool               // Whether the option takes a value ("key=value") or is a flag ("key")
+	validate func(string) error // Optional validation of the value
+	apply    func(f *FieldInfo, value string)
+	rule     bool // Whether the option is a validation rule checked by the constructors
+}
+
+// tagOptionSpecs lists the recognized options of the constructor struct tag,
+// e.g., `constructor:"name=ListenPort,getter:false"`
+var tagOptionSpecs = map[string]tagOptionSpec{
+	"-": {
+		apply: func(f *FieldInfo, _ string) { f.Skip = true },
+	},
+	"getter:false": {
+		apply: func(f *FieldInfo, _ string) { f.SkipGetter = true },
+	},
+	"setter:false": {
+		apply: func(f *FieldInfo, _ string) { f.SkipSetter = true },
+	},
+	"name": {
+		hasValue: true,
+		validate: validateIdentifier,
+		apply:    func(f *FieldInfo, value string) { f.NameOverride = value },
+	},
+	"getter": {
+		hasValue: true,
+		validate: validateIdentifier,
+		apply:    func(f *FieldInfo, value string) { f.GetterName = value },
+	},
+	"item": {
+		hasValue: true,
+		validate: validateIdentifier,
+		apply:    func(f *FieldInfo, value string) { f.ItemName = value },
+	},
+	"required": {
+		apply: func(f *FieldInfo, _ string) { f.Required = true },
+	},
+	// The embedded struct itself is replaced by its fields
+	"flatten": {
+		apply: func(f *FieldInfo, _ string) { f.Flatten, f.Skip = true, true },
+	},
+	// The presence bitmask is internal state, left out of all generated APIs
+	"presence": {
+		apply: func(f *FieldInfo, _ string) { f.Presence, f.Skip = true, true },
+	},
+	"default": {
+		hasValue: true,
+		validate: validateDefault,
+		apply:    func(f *FieldInfo, value string) { f.Default = value },
+	},
+	"min":      {hasValue: true, rule: true},
+	"max":      {hasValue: true, rule: true},
+	"nonempty": {rule: true},
+	"match":    {hasValue: true, rule: true, validate: validateMatchRule},
+	"oneof":    {hasValue: true, rule: true, validate: validateOneOfRule},
+	"notnil":   {rule: true},
+}
+
+// validateIdentifier checks that a tag value is a valid Go identifier
+func validateIdentifier(value string) error {
+	if !token.IsIdentifier(value) {
+		return fmt.Errorf("%q is not a valid identifier", value)
+	}
+	return nil
+}
+
+// legacyTagKeys are the tags of similar tools whose "-" value skips a field
+var legacyTagKeys = []string{"newc", "gonstructor"}
+
+// parseFieldTag parses the constructor options of a struct tag, given either as
+// the raw literal from source (including its quotes) or already unquoted.
+// The legacy newc:"-" and gonstructor:"-" tags are reported as the "-" option.
+func parseFieldTag(tag string) ([]TagOption, error) {
+	if unquoted, err := strconv.Unquote(tag); err == nil {
+		tag = unquoted
+	}
+	structTag := reflect.StructTag(tag)
+
+	options := []TagOption{}
+	value, ok := structTag.Lookup("constructor")
+	if !ok && hasTagKey(tag, "constructor") {
+		return nil, fmt.Errorf("malformed struct tag %s", tag)
+	}
+	if ok {
+		parts, err := splitTagOptions(value)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := map[string]bool{}
+		for _, part := range parts {
+			key, value, hasValue := strings.Cut(part, "=")
+			key = strings.TrimSpace(key)
+
+			spec, ok := tagOptionSpecs[key]
+			if !ok {
+				return nil, fmt.Errorf("unknown constructor tag option %q", key)
+			}
+			if seen[key] {
+				return nil, fmt.Errorf("duplicate constructor tag option %q", key)
+			}
+			seen[key] = true
+
+			if spec.hasValue && !hasValue {
+				return nil, fmt.Errorf("constructor tag option %q requires a value (%s=...)", key, key)
+			}
+			if !spec.hasValue && hasValue {
+				return nil, fmt.Errorf("constructor tag option %q does not take a value", key)
+			}
+			// Single quotes only protect commas, they are not part of the value
+			if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+				value = value[1 : len(value)-1]
+			}
+			if spec.validate != nil {
+				if err := spec.validate(value); err != nil {
+					return nil, fmt.Errorf("constructor tag option %q: %w", key, err)
+				}
+			}
+
+			options = append(options, TagOption{Key: key, Value: value})
+		}
+
+		if seen["-"] && len(options) > 1 {
+			return nil, fmt.Errorf(`constructor tag option "-" cannot be combined with other options`)
+		}
+		if seen["flatten"] && len(options) > 1 {
+			return nil, fmt.Errorf(`constructor tag option "flatten" cannot be combined with other options`)
+		}
+		if seen["presence"] && len(options) > 1 {
+			return nil, fmt.Errorf(`constructor tag option "presence" cannot be combined with other options`)
+		}
+		if seen["required"] && seen["setter:false"] {
+			return nil, fmt.Errorf(`constructor tag option "required" cannot be combined with "setter:false"`)
+		}
+		if seen["getter"] && seen["getter:false"] {
+			return nil, fmt.Errorf(`constructor tag option "getter" cannot be combined with "getter:false"`)
+		}
+		if seen["required"] && seen["default"] {
+			return nil, fmt.Errorf(`constructor tag option "required" cannot be combined with "default"`)
+		}
+	}
+
+	for _, key := range legacyTagKeys {
+		if value, ok := structTag.Lookup(key); ok && value == "-" {
+			options = append(options, TagOption{Key: "-"})
+			break
+		}
+	}
+
+	return options, nil
+}
+
+// hasTagKey reports whether key appears as a key in tag, even if the tag is too
+// malformed for reflect.StructTag to look it up
+func hasTagKey(tag, key string) bool {
+	for _, field := range strings.Fields(tag) {
+		if strings.HasPrefix(field, key+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTagOptions splits a constructor tag value on top-level commas. Commas
+// nested in (), [] or {}, or quoted with '...' do not split, so values such as
+// "expr:max(1, 2)" or "^[a-z]{1,3}$" stay intact.
+// Surrounding whitespace of each option is removed and empty options are rejected.
+func splitTagOptions(value string) ([]string, error) {
+	parts := []string{}
+	var current strings.Builder
+	depth := 0
+	inQuote := false
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in constructor tag %q", value)
+	}
+	parts = append(parts, current.String())
+
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+		if parts[i] == "" {
+			return nil, fmt.Errorf("empty option in constructor tag %q", value)
+		}
+	}
+	return parts, nil
+}
+
+// applyTagOptions parses the tag of field and applies its options
+func applyTagOptions(field *FieldInfo) error {
+	options, err := parseFieldTag(field.Tag)
+	if err != nil {
+		return err
+	}
+
+	field.Options = options
+	for _, option := range options {
+		if apply := tagOptionSpecs[option.Key].apply; apply != nil {
+			apply(field, option.Value)
+		}
+	}
+	return nil
+}
+
+// Option returns the value of the constructor tag option key and whether it is set
+func (f FieldInfo) Option(key string) (string, bool) {
+	for _, option := range f.Options {
+		if option.Key == key {
+			return option.Value, true
+		}
+	}
+	return "", false
+}
+
+// Rules returns the validation rules of the constructor tag, in tag order
+func (f FieldInfo) Rules() []TagOption {
+	rules := []TagOption{}
+	for _, option := range f.Options {
+		if tagOptionSpecs[option.Key].rule {
+			rules = append(rules, option)
+		}
+	}
+	return rules
+}
+
+// HasOption reports whether the constructor tag option key is set
+func (f FieldInfo) HasOption(key string) bool {
+	_, ok := f.Option(key)
+	return ok
+}
+
+// APIName returns the name the field is exposed under in generated code:
+// the name=... tag override if present, the field name otherwise
+func (f FieldInfo) APIName() string {
+	if f.NameOverride != "" {
+		return f.NameOverride
+	}
+	return f.Name
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFieldTag(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    string
+		expect []TagOption
+	}{
+		{"no tag", "", []TagOption{}},
+		{"raw literal", "`constructor:\"getter:false\"`", []TagOption{{Key: "getter:false"}}},
+		{"unquoted", `json:"port" constructor:"name=ListenPort,setter:false"`, []TagOption{{Key: "name", Value: "ListenPort"}, {Key: "setter:false"}}},
+		{"spaces around options", `constructor:" getter:false , name=Port "`, []TagOption{{Key: "getter:false"}, {Key: "name", Value: "Port"}}},
+		{"quoted value", `constructor:"name='Port'"`, []TagOption{{Key: "name", Value: "Port"}}},
+		{"legacy skip", `newc:"-"`, []TagOption{{Key: "-"}}},
+		{"default expression", `constructor:"default=expr:max(1, 2)"`, []TagOption{{Key: "default", Value: "expr:max(1, 2)"}}},
+		{"getter name", `constructor:"getter=IsEnabled"`, []TagOption{{Key: "getter", Value: "IsEnabled"}}},
+		{"presence", `constructor:"presence"`, []TagOption{{Key: "presence"}}},
+		{"flatten", `constructor:"flatten"`, []TagOption{{Key: "flatten"}}},
+		{"item name", `constructor:"item=alias"`, []TagOption{{Key: "item", Value: "alias"}}},
+		{"other tags only", `json:"name,omitempty"`, []TagOption{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := parseFieldTag(tt.tag)
+			if err != nil {
+				t.Fatalf("parseFieldTag(%q) failed: %v", tt.tag, err)
+			}
+			if !reflect.DeepEqual(options, tt.expect) {
+				t.Errorf("parseFieldTag(%q) = %v, want %v", tt.tag, options, tt.expect)
+			}
+		})
+	}
+}
+
+func TestParseFieldTagErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		expectErr string
+	}{
+		{"unknown option", `constructor:"getter:true"`, `unknown constructor tag option "getter:true"`},
+		{"duplicate option", `constructor:"setter:false,setter:false"`, `duplicate constructor tag option "setter:false"`},
+		{"missing value", `constructor:"name"`, `requires a value`},
+		{"unexpected value", `constructor:"getter:false=1"`, `does not take a value`},
+		{"invalid name", `constructor:"name=listen-port"`, `not a valid identifier`},
+		{"empty option", `constructor:"getter:false,,setter:false"`, `empty option`},
+		{"unterminated quote", `constructor:"name='Port"`, `unterminated quote`},
+		{"skip combined", `constructor:"-,getter:false"`, `cannot be combined`},
+		{"malformed tag", `constructor:getter:false`, `malformed struct tag`},
+		{"required without setter", `constructor:"required,setter:false"`, `cannot be combined with "setter:false"`},
+		{"required with default", `constructor:"required,default=1"`, `cannot be combined with "default"`},
+		{"getter name without getter", `constructor:"getter=Port,getter:false"`, `cannot be combined with "getter:false"`},
+		{"invalid getter name", `constructor:"getter=is-enabled"`, `not a valid identifier`},
+		{"presence combined", `constructor:"presence,getter:false"`, `"presence" cannot be combined`},
+		{"flatten combined", `constructor:"flatten,required"`, `"flatten" cannot be combined`},
+		{"invalid pattern", `constructor:"match=^[a-z+$"`, `missing closing ]`},
+		{"empty alternative", `constructor:"oneof=dev||prod"`, `empty alternative`},
+		{"empty default", `constructor:"default=''"`, `must not be empty`},
+		{"invalid default expression", `constructor:"default=expr:runtime.NumCPU("`, `invalid expression`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFieldTag(tt.tag)
+			if err == nil {
+				t.Fatalf("parseFieldTag(%q) expected an error", tt.tag)
+			}
+			if !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("parseFieldTag(%q) error = %q, want it to contain %q", tt.tag, err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestSplitTagOptions(t *testing.T) {
+	tests := []struct {
+		value  string
+		expect []string
+	}{
+		{"a", []string{"a"}},
+		{"a,b=1", []string{"a", "b=1"}},
+		{"expr=max(1, 2),b", []string{"expr=max(1, 2)", "b"}},
+		{"re=^[a-z]{1,3}$,b", []string{"re=^[a-z]{1,3}$", "b"}},
+		{"list='a,b',c", []string{"list='a,b'", "c"}},
+	}
+
+	for _, tt := range tests {
+		parts, err := splitTagOptions(tt.value)
+		if err != nil {
+			t.Fatalf("splitTagOptions(%q) failed: %v", tt.value, err)
+		}
+		if !reflect.DeepEqual(parts, tt.expect) {
+			t.Errorf("splitTagOptions(%q) = %q, want %q", tt.value, parts, tt.expect)
+		}
+	}
+}
+
+func TestParseStructTagOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+
+	content := `package test
+
+type Server struct {
+	host string
+	port int ` + "`" + `constructor:"name=ListenPort,getter:false"` + "`" + `
+}
+
+type Broken struct {
+	host string
+	port int ` + "`" + `constructor:"getter:true"` + "`" + `
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ParseStruct(testFile, "Server")
+	if err != nil {
+		t.Fatalf("ParseStruct failed: %v", err)
+	}
+	port := info.Fields[1]
+	if port.APIName() != "ListenPort" || !port.SkipGetter || port.SkipSetter {
+		t.Errorf("unexpected port field: %+v", port)
+	}
+	if value, ok := port.Option("name"); !ok || value != "ListenPort" {
+		t.Errorf("Option(name) = (%q, %v), want (\"ListenPort\", true)", value, ok)
+	}
+
+	// Unknown options are reported with the position of the field
+	_, err = ParseStruct(testFile, "Broken")
+	if err == nil {
+		t.Fatal("expected an error for an unknown tag option")
+	}
+	if !strings.Contains(err.Error(), "test.go:10: field port:") {
+		t.Errorf("error should point at the field, got: %v", err)
+	}
+}
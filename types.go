@@ -1,30 +1,101 @@
 package main
 
+import "go/types"
+
 // StructInfo represents parsed struct information
 type StructInfo struct {
-	Name        string      // Struct name, e.g., "User"
-	Fields      []FieldInfo // List of fields
-	PackageName string      // Package name
+	Name        string       // Struct name, e.g., "User"
+	TypeParams  []TypeParam  // Type parameters of a generic struct, in declaration order
+	Fields      []FieldInfo  // List of fields
+	PackageName string       // Package name
+	Imports     []ImportInfo // Imports referenced by field types (type-checked loading only)
+	Annotated   bool         // Whether the struct doc comment carries a //constructor:gen directive
+	Directive   string       // Arguments of the //constructor:gen directive, e.g., "builder,options init=setup"
+	Doc         string       // Doc comment of the struct without directives such as //constructor:gen
+	Methods     []MethodInfo // Methods declared on the struct (promoted methods too with type-checked loading)
+
+	Builders map[string]BuilderInfo // Builders declared in the package usable by nested builders, keyed by the struct they build
+}
+
+// BuilderInfo describes the builder of another struct, as used by nested builders
+type BuilderInfo struct {
+	ReturnsValue bool // Whether Build returns the struct rather than a pointer to it
+}
+
+// MethodInfo represents the signature of a method of a struct
+type MethodInfo struct {
+	Name    string   // Method name, e.g., "Validate"
+	Params  []string // Parameter types, e.g., ["context.Context"]
+	Results []string // Result types, e.g., ["error"]
+
+	Generated bool // Whether the method is declared in a generated file
+}
+
+// ImportInfo represents an import required by the generated code
+type ImportInfo struct {
+	Name string // Import name if the source file uses an alias, e.g., "tm"; empty otherwise
+	Path string // Import path, e.g., "time"
+}
+
+// TypeParam represents a single type parameter of a generic struct
+type TypeParam struct {
+	Name       string // Type parameter name, e.g., "K"
+	Constraint string // Type constraint, e.g., "comparable", "~int | ~string"
 }
 
 // FieldInfo represents a single field in a struct
 type FieldInfo struct {
-	Name       string // Field name, e.g., "userName"
-	Type       string // Field type, e.g., "*string", "int"
-	Tag        string // Field tag, e.g., `json:"user_name" constructor:"-"`
-	Exported   bool   // Whether the field is exported (uppercase first letter)
-	Skip       bool   // Whether to skip this field completely (from tag `constructor:"-"`)
-	SkipGetter bool   // Whether to skip getter generation (from tag `constructor:"getter:false"`)
-	SkipSetter bool   // Whether to skip setter/constructor parameter (from tag `constructor:"setter:false"`)
+	Name         string      // Field name, e.g., "userName"
+	Type         string      // Field type, e.g., "*string", "int"
+	Tag          string      // Field tag, e.g., `json:"user_name" constructor:"-"`
+	Exported     bool        // Whether the field is exported (uppercase first letter)
+	Skip         bool        // Whether to skip this field completely (from tag `constructor:"-"`)
+	SkipGetter   bool        // Whether to skip getter generation (from tag `constructor:"getter:false"`)
+	SkipSetter   bool        // Whether to skip setter/constructor parameter (from tag `constructor:"setter:false"`)
+	NameOverride string      // Name used in generated APIs instead of the field name (from tag `constructor:"name=..."`)
+	GetterName   string      // Name of the getter instead of the one derived from the field (from tag `constructor:"getter=..."`)
+	ItemName     string      // Name of a single item of a slice or map field in collection helpers (from tag `constructor:"item=..."`)
+	Required     bool        // Whether the field must be set by builders and options (from tag `constructor:"required"`)
+	Default      string      // Default value, a literal or "expr:<Go expression>" (from tag `constructor:"default=..."`)
+	Presence     bool        // Whether the field is the bitmask recording which fields were set (from tag `constructor:"presence"`)
+	Embedded     bool        // Whether the field is embedded; its name is then the unqualified type name
+	Flatten      bool        // Whether constructors set the fields of the embedded struct individually (from tag `constructor:"flatten"`)
+	Parent       string      // Embedded field holding the field, for fields of a flattened struct; empty otherwise
+	Options      []TagOption // Parsed options of the constructor tag, in tag order
+	Pos          string      // Source position of the field, e.g., "user.go:12"
+	Doc          string      // Doc comment of the field, or its line comment if it has none, without comment markers
+	Deprecated   string      // Notice of the "Deprecated:" paragraph of Doc, without the prefix; empty if not deprecated
+
+	TypeInfo types.Type // Resolved field type (type-checked loading only, nil otherwise)
+}
+
+// TagOption is a single option of the constructor struct tag, e.g., "name=ListenPort"
+type TagOption struct {
+	Key   string // Option key, e.g., "name", "getter:false"
+	Value string // Option value after "=", empty for flag options
 }
 
 // GeneratorConfig holds configuration for code generation
 type GeneratorConfig struct {
-	StructName       string   // Target struct name
-	ConstructorTypes []string // Types: "allArgs", "builder", "options"
-	OutputFile       string   // Output file path
-	InitFunc         string   // Initialization function name (optional)
-	ReturnValue      bool     // Return value instead of pointer
-	SetterPrefix     string   // Prefix for setter methods in builder (e.g., "With")
-	WithGetter       bool     // Generate getter methods
+	StructName        string   // Target struct name
+	ConstructorTypes  []string // Types: "allArgs", "builder", "options", "stepBuilder", "withers"
+	OutputFile        string   // Output file path
+	InitFunc          string   // Initialization function name (optional)
+	ReturnValue       bool     // Return value instead of pointer
+	SetterPrefix      string   // Prefix for setter methods in builder (e.g., "With")
+	ToBuilder         bool     // Generate NewXBuilderFrom and ToBuilder alongside the builder
+	OptionStyle       string   // Option type of the options constructor: "func" (default), "interface" or "error"
+	OptionNaming      string   // Option function naming: "field" (default), "prefixed", "namespace" or "auto"
+	WithGetter        bool     // Generate getter methods
+	GetterStyle       string   // Getter naming: "get" (GetName, default) or "plain" (Name)
+	Receiver          string   // Receiver of getter methods: "pointer" (default) or "value"
+	WithSetter        bool     // Generate setter methods
+	FluentSetter      bool     // Setter methods return the receiver
+	SetterChecks      bool     // Setter methods check required fields and field rules
+	ValidateFunc      string   // Validation method name, "-" to disable detection of Validate() error (optional)
+	AllArgsDefaults   bool     // Apply field defaults to zero arguments of the allArgs constructor
+	OmitDeprecated    bool     // Leave deprecated fields out of the allArgs constructor parameters
+	WithersDeepCopy   bool     // Clone slice and map fields in wither methods
+	CollectionHelpers bool     // Generate builder methods and options adding items to slice and map fields
+	NestedBuilders    bool     // Generate builder methods configuring struct fields with the builders of their types
 }
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+)
+
+// generateWithers generates With<Field> methods returning a copy of the struct
+// with one field changed, for immutable value types. The receiver is a value
+// with -returnValue and a pointer otherwise. With -withersDeepCopy, slice and
+// map fields are cloned so that the copy does not share them with the original.
+func (g *Generator) generateWithers(fields []FieldInfo) (string, error) {
+	var buf bytes.Buffer
+
+	receiverName := g.receiverName()
+	copyName := "clone"
+	for _, field := range fields {
+		if paramName := toLowerCamelCase(field.APIName()); paramName == receiverName || paramName == copyName {
+			return "", fmt.Errorf("%s: field %s clashes with the variable %s of the wither methods", field.Pos, field.Name, paramName)
+		}
+	}
+
+	for _, field := range fields {
+		methodName := "With" + toUpperCamelCase(field.APIName())
+		paramName := toLowerCamelCase(field.APIName())
+
+		buf.WriteString(fmt.Sprintf("// %s returns a copy of %s with the %s field set\n", methodName, receiverName, field.Name))
+		buf.WriteString(docComment(field, ""))
+
+		// A value receiver is already a copy; a pointer receiver is copied first
+		target := receiverName
+		if g.config.ReturnValue {
+			buf.WriteString(fmt.Sprintf("func (%s %s) %s(%s %s) %s {\n",
+				receiverName, g.typeName(), methodName, paramName, field.Type, g.typeName()))
+		} else {
+			target = copyName
+			buf.WriteString(fmt.Sprintf("func (%s *%s) %s(%s %s) *%s {\n",
+				receiverName, g.typeName(), methodName, paramName, field.Type, g.typeName()))
+			buf.WriteString(fmt.Sprintf("\t%s := *%s\n", copyName, receiverName))
+		}
+
+		if g.config.WithersDeepCopy {
+			for _, other := range g.info.Fields {
+				if other.Skip || other.Name == field.Name {
+					continue
+				}
+				if clone := cloneExpr(other, receiverName+"."+other.Path()); clone != "" {
+					buf.WriteString(fmt.Sprintf("\t%s.%s = %s\n", target, other.Path(), clone))
+				}
+			}
+		}
+
+		buf.WriteString(fmt.Sprintf("\t%s.%s = %s\n", target, field.Path(), paramName))
+		buf.WriteString(g.presenceMark("\t", target, field))
+		if g.config.ReturnValue {
+			buf.WriteString(fmt.Sprintf("\treturn %s\n", target))
+		} else {
+			buf.WriteString(fmt.Sprintf("\treturn &%s\n", target))
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.String(), nil
+}
+
+// cloneExpr returns an expression copying the slice or map expr of the type of
+// field, e.g., "slices.Clone(u.tags)", or an empty string for other types.
+// Elements are copied shallowly.
+func cloneExpr(field FieldInfo, expr string) string {
+	if kind, _ := classifyType(field); kind != kindCollection {
+		return ""
+	}
+
+	isMap := false
+	if field.TypeInfo != nil {
+		_, isMap = field.TypeInfo.Underlying().(*types.Map)
+	} else if typeExpr, err := parser.ParseExpr(field.Type); err == nil {
+		_, isMap = typeExpr.(*ast.MapType)
+	}
+
+	if isMap {
+		return fmt.Sprintf("maps.Clone(%s)", expr)
+	}
+	return fmt.Sprintf("slices.Clone(%s)", expr)
+}